@@ -22,6 +22,37 @@ It has these top-level messages:
 	CreateAccountRequest
 	UpdateAccountRequest
 	DeleteAccountRequest
+	Session
+	CreateSessionRequest
+	RefreshSessionRequest
+	RevokeSessionRequest
+	ListSessionsRequest
+	ListSessionsResponse
+	RevokeAllSessionsRequest
+	IssueTokenRequest
+	IssueTokenResponse
+	GetPublicKeysRequest
+	PublicKey
+	GetPublicKeysResponse
+	MfaChallenge
+	EnrolMfaRequest
+	EnrolMfaResponse
+	ActivateMfaRequest
+	DisableMfaRequest
+	GenerateRecoveryCodesRequest
+	GenerateRecoveryCodesResponse
+	VerifyMfaRequest
+	UnlockAccountRequest
+	RequestLoginLinkRequest
+	RequestLoginLinkResponse
+	ConsumeLoginLinkRequest
+	ConsumeLoginLinkResponse
+	RequestEmailChangeRequest
+	EmailChange
+	ConfirmEmailChangeRequest
+	ResendConfirmationRequest
+	ResendConfirmationResponse
+	ValidateSessionRequest
 */
 package account_service
 
@@ -30,6 +61,8 @@ import fmt "fmt"
 import math "math"
 import google_protobuf "github.com/golang/protobuf/ptypes/empty"
 import image_service "github.com/lileio/image_service"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+import google_protobuf2 "google.golang.org/genproto/protobuf/field_mask"
 
 import (
 	context "golang.org/x/net/context"
@@ -47,6 +80,59 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type ConfirmedFilter int32
+
+const (
+	ConfirmedFilter_CONFIRMED_FILTER_ANY         ConfirmedFilter = 0
+	ConfirmedFilter_CONFIRMED_FILTER_CONFIRMED   ConfirmedFilter = 1
+	ConfirmedFilter_CONFIRMED_FILTER_UNCONFIRMED ConfirmedFilter = 2
+)
+
+var ConfirmedFilter_name = map[int32]string{
+	0: "CONFIRMED_FILTER_ANY",
+	1: "CONFIRMED_FILTER_CONFIRMED",
+	2: "CONFIRMED_FILTER_UNCONFIRMED",
+}
+var ConfirmedFilter_value = map[string]int32{
+	"CONFIRMED_FILTER_ANY":         0,
+	"CONFIRMED_FILTER_CONFIRMED":   1,
+	"CONFIRMED_FILTER_UNCONFIRMED": 2,
+}
+
+func (x ConfirmedFilter) String() string {
+	return proto.EnumName(ConfirmedFilter_name, int32(x))
+}
+func (ConfirmedFilter) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+// AccountView controls which fields of an account are returned by reads
+type AccountView int32
+
+const (
+	// every field except secrets such as tokens
+	AccountView_FULL AccountView = 0
+	// only id, name, email and version
+	AccountView_BASIC AccountView = 1
+	// FULL along with the digests of any outstanding confirmation and
+	// password reset tokens, in confirm_token and password_reset_token
+	AccountView_ADMIN AccountView = 2
+)
+
+var AccountView_name = map[int32]string{
+	0: "FULL",
+	1: "BASIC",
+	2: "ADMIN",
+}
+var AccountView_value = map[string]int32{
+	"FULL":  0,
+	"BASIC": 1,
+	"ADMIN": 2,
+}
+
+func (x AccountView) String() string {
+	return proto.EnumName(AccountView_name, int32(x))
+}
+func (AccountView) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type Account struct {
 	Id                 string                          `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Name               string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
@@ -55,6 +141,18 @@ type Account struct {
 	ConfirmToken       string                          `protobuf:"bytes,5,opt,name=confirm_token,json=confirmToken" json:"confirm_token,omitempty"`
 	PasswordResetToken string                          `protobuf:"bytes,6,opt,name=password_reset_token,json=passwordResetToken" json:"password_reset_token,omitempty"`
 	Metadata           map[string]string               `protobuf:"bytes,7,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// incremented on every write, see UpdateAccountRequest.version
+	Version int64 `protobuf:"varint,8,opt,name=version" json:"version,omitempty"`
+	// digests of any outstanding tokens, only set in the ADMIN view
+	ConfirmTokenHash       string `protobuf:"bytes,9,opt,name=confirm_token_hash,json=confirmTokenHash" json:"confirm_token_hash,omitempty"`
+	PasswordResetTokenHash string `protobuf:"bytes,10,opt,name=password_reset_token_hash,json=passwordResetTokenHash" json:"password_reset_token_hash,omitempty"`
+	Confirmed              bool   `protobuf:"varint,12,opt,name=confirmed" json:"confirmed,omitempty"`
+	// accounts from before these were recorded only have them once they're
+	// next written to, confirmed or logged in to
+	CreatedAt   *google_protobuf1.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	UpdatedAt   *google_protobuf1.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
+	ConfirmedAt *google_protobuf1.Timestamp `protobuf:"bytes,15,opt,name=confirmed_at,json=confirmedAt" json:"confirmed_at,omitempty"`
+	LastLoginAt *google_protobuf1.Timestamp `protobuf:"bytes,16,opt,name=last_login_at,json=lastLoginAt" json:"last_login_at,omitempty"`
 }
 
 func (m *Account) Reset()                    { *m = Account{} }
@@ -111,9 +209,79 @@ func (m *Account) GetMetadata() map[string]string {
 	return nil
 }
 
+func (m *Account) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Account) GetConfirmTokenHash() string {
+	if m != nil {
+		return m.ConfirmTokenHash
+	}
+	return ""
+}
+
+func (m *Account) GetPasswordResetTokenHash() string {
+	if m != nil {
+		return m.PasswordResetTokenHash
+	}
+	return ""
+}
+
+func (m *Account) GetConfirmed() bool {
+	if m != nil {
+		return m.Confirmed
+	}
+	return false
+}
+
+func (m *Account) GetCreatedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Account) GetUpdatedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Account) GetConfirmedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ConfirmedAt
+	}
+	return nil
+}
+
+func (m *Account) GetLastLoginAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.LastLoginAt
+	}
+	return nil
+}
+
 type ListAccountsRequest struct {
 	PageSize  int32  `protobuf:"varint,1,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
 	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+	// case insensitive
+	EmailPrefix string `protobuf:"bytes,3,opt,name=email_prefix,json=emailPrefix" json:"email_prefix,omitempty"`
+	// case insensitive
+	NameContains string `protobuf:"bytes,4,opt,name=name_contains,json=nameContains" json:"name_contains,omitempty"`
+	// exclusive bounds on the account creation time
+	CreatedAfter  *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter" json:"created_after,omitempty"`
+	CreatedBefore *google_protobuf1.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore" json:"created_before,omitempty"`
+	Confirmed     ConfirmedFilter             `protobuf:"varint,7,opt,name=confirmed,enum=account_service.ConfirmedFilter" json:"confirmed,omitempty"`
+	// accounts must have every key/value pair given
+	Metadata map[string]string `protobuf:"bytes,8,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// one of created_at, email or name optionally followed by asc or desc
+	// i.e "email desc", defaults to "created_at asc"
+	OrderBy string      `protobuf:"bytes,9,opt,name=order_by,json=orderBy" json:"order_by,omitempty"`
+	View    AccountView `protobuf:"varint,10,opt,name=view,enum=account_service.AccountView" json:"view,omitempty"`
 }
 
 func (m *ListAccountsRequest) Reset()                    { *m = ListAccountsRequest{} }
@@ -135,6 +303,62 @@ func (m *ListAccountsRequest) GetPageToken() string {
 	return ""
 }
 
+func (m *ListAccountsRequest) GetEmailPrefix() string {
+	if m != nil {
+		return m.EmailPrefix
+	}
+	return ""
+}
+
+func (m *ListAccountsRequest) GetNameContains() string {
+	if m != nil {
+		return m.NameContains
+	}
+	return ""
+}
+
+func (m *ListAccountsRequest) GetCreatedAfter() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return nil
+}
+
+func (m *ListAccountsRequest) GetCreatedBefore() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return nil
+}
+
+func (m *ListAccountsRequest) GetConfirmed() ConfirmedFilter {
+	if m != nil {
+		return m.Confirmed
+	}
+	return ConfirmedFilter_CONFIRMED_FILTER_ANY
+}
+
+func (m *ListAccountsRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ListAccountsRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+func (m *ListAccountsRequest) GetView() AccountView {
+	if m != nil {
+		return m.View
+	}
+	return AccountView_FULL
+}
+
 type ListAccountsResponse struct {
 	Accounts      []*Account `protobuf:"bytes,1,rep,name=accounts" json:"accounts,omitempty"`
 	NextPageToken string     `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
@@ -160,7 +384,8 @@ func (m *ListAccountsResponse) GetNextPageToken() string {
 }
 
 type GetByIdRequest struct {
-	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Id   string      `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	View AccountView `protobuf:"varint,2,opt,name=view,enum=account_service.AccountView" json:"view,omitempty"`
 }
 
 func (m *GetByIdRequest) Reset()                    { *m = GetByIdRequest{} }
@@ -175,8 +400,16 @@ func (m *GetByIdRequest) GetId() string {
 	return ""
 }
 
+func (m *GetByIdRequest) GetView() AccountView {
+	if m != nil {
+		return m.View
+	}
+	return AccountView_FULL
+}
+
 type GetByEmailRequest struct {
-	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Email string      `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	View  AccountView `protobuf:"varint,2,opt,name=view,enum=account_service.AccountView" json:"view,omitempty"`
 }
 
 func (m *GetByEmailRequest) Reset()                    { *m = GetByEmailRequest{} }
@@ -191,6 +424,13 @@ func (m *GetByEmailRequest) GetEmail() string {
 	return ""
 }
 
+func (m *GetByEmailRequest) GetView() AccountView {
+	if m != nil {
+		return m.View
+	}
+	return AccountView_FULL
+}
+
 type AuthenticateByEmailRequest struct {
 	Email    string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
@@ -233,6 +473,8 @@ func (m *GeneratePasswordTokenRequest) GetEmail() string {
 
 type GeneratePasswordTokenResponse struct {
 	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	// the token can't be used to reset the password after this time
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
 }
 
 func (m *GeneratePasswordTokenResponse) Reset()                    { *m = GeneratePasswordTokenResponse{} }
@@ -247,6 +489,13 @@ func (m *GeneratePasswordTokenResponse) GetToken() string {
 	return ""
 }
 
+func (m *GeneratePasswordTokenResponse) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
 type ResetPasswordRequest struct {
 	Token    string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
@@ -320,10 +569,17 @@ func (m *CreateAccountRequest) GetImage() *image_service.ImageStoreRequest {
 }
 
 type UpdateAccountRequest struct {
-	Id       string                           `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// new password, the password is left alone when empty
 	Password string                           `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
 	Image    *image_service.ImageStoreRequest `protobuf:"bytes,3,opt,name=image" json:"image,omitempty"`
 	Account  *Account                         `protobuf:"bytes,4,opt,name=account" json:"account,omitempty"`
+	// fields of account to update, one or both of name and metadata.
+	// Both are updated when empty
+	UpdateMask *google_protobuf2.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask" json:"update_mask,omitempty"`
+	// expected account version, the update is aborted if the account has
+	// changed since. Ignored when 0
+	Version int64 `protobuf:"varint,6,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *UpdateAccountRequest) Reset()                    { *m = UpdateAccountRequest{} }
@@ -359,8 +615,24 @@ func (m *UpdateAccountRequest) GetAccount() *Account {
 	return nil
 }
 
+func (m *UpdateAccountRequest) GetUpdateMask() *google_protobuf2.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateAccountRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type DeleteAccountRequest struct {
 	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// expected account version, ignored when 0
+	Version int64 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *DeleteAccountRequest) Reset()                    { *m = DeleteAccountRequest{} }
@@ -375,145 +647,1199 @@ func (m *DeleteAccountRequest) GetId() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*Account)(nil), "account_service.Account")
-	proto.RegisterType((*ListAccountsRequest)(nil), "account_service.ListAccountsRequest")
-	proto.RegisterType((*ListAccountsResponse)(nil), "account_service.ListAccountsResponse")
-	proto.RegisterType((*GetByIdRequest)(nil), "account_service.GetByIdRequest")
-	proto.RegisterType((*GetByEmailRequest)(nil), "account_service.GetByEmailRequest")
-	proto.RegisterType((*AuthenticateByEmailRequest)(nil), "account_service.AuthenticateByEmailRequest")
-	proto.RegisterType((*GeneratePasswordTokenRequest)(nil), "account_service.GeneratePasswordTokenRequest")
-	proto.RegisterType((*GeneratePasswordTokenResponse)(nil), "account_service.GeneratePasswordTokenResponse")
-	proto.RegisterType((*ResetPasswordRequest)(nil), "account_service.ResetPasswordRequest")
-	proto.RegisterType((*ConfirmAccountRequest)(nil), "account_service.ConfirmAccountRequest")
-	proto.RegisterType((*CreateAccountRequest)(nil), "account_service.CreateAccountRequest")
-	proto.RegisterType((*UpdateAccountRequest)(nil), "account_service.UpdateAccountRequest")
-	proto.RegisterType((*DeleteAccountRequest)(nil), "account_service.DeleteAccountRequest")
+func (m *DeleteAccountRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// Client API for AccountService service
-
-type AccountServiceClient interface {
-	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
-	GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Account, error)
-	GetByEmail(ctx context.Context, in *GetByEmailRequest, opts ...grpc.CallOption) (*Account, error)
-	AuthenticateByEmail(ctx context.Context, in *AuthenticateByEmailRequest, opts ...grpc.CallOption) (*Account, error)
-	GeneratePasswordToken(ctx context.Context, in *GeneratePasswordTokenRequest, opts ...grpc.CallOption) (*GeneratePasswordTokenResponse, error)
-	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Account, error)
-	ConfirmAccount(ctx context.Context, in *ConfirmAccountRequest, opts ...grpc.CallOption) (*Account, error)
-	Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
-	Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*Account, error)
-	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+// Session is a login on a device. The access token identifies it until it
+// expires, the refresh token can then be exchanged for new tokens.
+type Session struct {
+	Id        string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	AccountId string `protobuf:"bytes,2,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+	// only returned by CreateSession and RefreshSession
+	AccessToken           string                      `protobuf:"bytes,3,opt,name=access_token,json=accessToken" json:"access_token,omitempty"`
+	RefreshToken          string                      `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
+	AccessTokenExpiresAt  *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=access_token_expires_at,json=accessTokenExpiresAt" json:"access_token_expires_at,omitempty"`
+	RefreshTokenExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,6,opt,name=refresh_token_expires_at,json=refreshTokenExpiresAt" json:"refresh_token_expires_at,omitempty"`
+	UserAgent             string                      `protobuf:"bytes,7,opt,name=user_agent,json=userAgent" json:"user_agent,omitempty"`
+	Device                string                      `protobuf:"bytes,8,opt,name=device" json:"device,omitempty"`
+	CreatedAt             *google_protobuf1.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	LastSeenAt            *google_protobuf1.Timestamp `protobuf:"bytes,10,opt,name=last_seen_at,json=lastSeenAt" json:"last_seen_at,omitempty"`
+}
+
+func (m *Session) Reset()                    { *m = Session{} }
+func (m *Session) String() string            { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()               {}
+func (*Session) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-type accountServiceClient struct {
-	cc *grpc.ClientConn
+func (m *Session) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
 }
 
-func NewAccountServiceClient(cc *grpc.ClientConn) AccountServiceClient {
-	return &accountServiceClient{cc}
+func (m *Session) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
 }
 
-func (c *accountServiceClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
-	out := new(ListAccountsResponse)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/List", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountServiceClient) GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/GetById", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetAccessTokenExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.AccessTokenExpiresAt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *accountServiceClient) GetByEmail(ctx context.Context, in *GetByEmailRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/GetByEmail", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetRefreshTokenExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.RefreshTokenExpiresAt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *accountServiceClient) AuthenticateByEmail(ctx context.Context, in *AuthenticateByEmailRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/AuthenticateByEmail", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountServiceClient) GeneratePasswordToken(ctx context.Context, in *GeneratePasswordTokenRequest, opts ...grpc.CallOption) (*GeneratePasswordTokenResponse, error) {
-	out := new(GeneratePasswordTokenResponse)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/GeneratePasswordToken", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetDevice() string {
+	if m != nil {
+		return m.Device
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/ResetPassword", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetCreatedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedAt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *accountServiceClient) ConfirmAccount(ctx context.Context, in *ConfirmAccountRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/ConfirmAccount", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *Session) GetLastSeenAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.LastSeenAt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *accountServiceClient) Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/Create", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type CreateSessionRequest struct {
+	Email     string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Password  string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+	UserAgent string `protobuf:"bytes,3,opt,name=user_agent,json=userAgent" json:"user_agent,omitempty"`
+	Device    string `protobuf:"bytes,4,opt,name=device" json:"device,omitempty"`
+	// a TOTP or recovery code, required when the account has MFA enabled
+	MfaCode string `protobuf:"bytes,5,opt,name=mfa_code,json=mfaCode" json:"mfa_code,omitempty"`
 }
 
-func (c *accountServiceClient) Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
-	out := new(Account)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/Update", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSessionRequest) Reset()                    { *m = CreateSessionRequest{} }
+func (m *CreateSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateSessionRequest) ProtoMessage()               {}
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *CreateSessionRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountServiceClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/account_service.AccountService/Delete", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSessionRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
 	}
-	return out, nil
+	return ""
 }
 
-// Server API for AccountService service
-
+func (m *CreateSessionRequest) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetDevice() string {
+	if m != nil {
+		return m.Device
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetMfaCode() string {
+	if m != nil {
+		return m.MfaCode
+	}
+	return ""
+}
+
+type RefreshSessionRequest struct {
+	RefreshToken string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken" json:"refresh_token,omitempty"`
+}
+
+func (m *RefreshSessionRequest) Reset()                    { *m = RefreshSessionRequest{} }
+func (m *RefreshSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefreshSessionRequest) ProtoMessage()               {}
+func (*RefreshSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RefreshSessionRequest) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type RevokeSessionRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *RevokeSessionRequest) Reset()                    { *m = RevokeSessionRequest{} }
+func (m *RevokeSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeSessionRequest) ProtoMessage()               {}
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *RevokeSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListSessionsRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *ListSessionsRequest) Reset()                    { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()               {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *ListSessionsRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type ListSessionsResponse struct {
+	Sessions []*Session `protobuf:"bytes,1,rep,name=sessions" json:"sessions,omitempty"`
+}
+
+func (m *ListSessionsResponse) Reset()                    { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()               {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *ListSessionsResponse) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type RevokeAllSessionsRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *RevokeAllSessionsRequest) Reset()                    { *m = RevokeAllSessionsRequest{} }
+func (m *RevokeAllSessionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeAllSessionsRequest) ProtoMessage()               {}
+func (*RevokeAllSessionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *RevokeAllSessionsRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type IssueTokenRequest struct {
+	Email    string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+	// a TOTP or recovery code, required when the account has MFA enabled
+	MfaCode string `protobuf:"bytes,3,opt,name=mfa_code,json=mfaCode" json:"mfa_code,omitempty"`
+}
+
+func (m *IssueTokenRequest) Reset()                    { *m = IssueTokenRequest{} }
+func (m *IssueTokenRequest) String() string            { return proto.CompactTextString(m) }
+func (*IssueTokenRequest) ProtoMessage()               {}
+func (*IssueTokenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *IssueTokenRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *IssueTokenRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *IssueTokenRequest) GetMfaCode() string {
+	if m != nil {
+		return m.MfaCode
+	}
+	return ""
+}
+
+type IssueTokenResponse struct {
+	// a JWT signed with the newest key returned by GetPublicKeys
+	Token     string                      `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *IssueTokenResponse) Reset()                    { *m = IssueTokenResponse{} }
+func (m *IssueTokenResponse) String() string            { return proto.CompactTextString(m) }
+func (*IssueTokenResponse) ProtoMessage()               {}
+func (*IssueTokenResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *IssueTokenResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *IssueTokenResponse) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+type GetPublicKeysRequest struct {
+}
+
+func (m *GetPublicKeysRequest) Reset()                    { *m = GetPublicKeysRequest{} }
+func (m *GetPublicKeysRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetPublicKeysRequest) ProtoMessage()               {}
+func (*GetPublicKeysRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+// PublicKey is a JSON Web Key, RSA keys set n and e and Ed25519 keys crv and x
+type PublicKey struct {
+	Kty string `protobuf:"bytes,1,opt,name=kty" json:"kty,omitempty"`
+	Kid string `protobuf:"bytes,2,opt,name=kid" json:"kid,omitempty"`
+	Use string `protobuf:"bytes,3,opt,name=use" json:"use,omitempty"`
+	Alg string `protobuf:"bytes,4,opt,name=alg" json:"alg,omitempty"`
+	N   string `protobuf:"bytes,5,opt,name=n" json:"n,omitempty"`
+	E   string `protobuf:"bytes,6,opt,name=e" json:"e,omitempty"`
+	Crv string `protobuf:"bytes,7,opt,name=crv" json:"crv,omitempty"`
+	X   string `protobuf:"bytes,8,opt,name=x" json:"x,omitempty"`
+}
+
+func (m *PublicKey) Reset()                    { *m = PublicKey{} }
+func (m *PublicKey) String() string            { return proto.CompactTextString(m) }
+func (*PublicKey) ProtoMessage()               {}
+func (*PublicKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *PublicKey) GetKty() string {
+	if m != nil {
+		return m.Kty
+	}
+	return ""
+}
+
+func (m *PublicKey) GetKid() string {
+	if m != nil {
+		return m.Kid
+	}
+	return ""
+}
+
+func (m *PublicKey) GetUse() string {
+	if m != nil {
+		return m.Use
+	}
+	return ""
+}
+
+func (m *PublicKey) GetAlg() string {
+	if m != nil {
+		return m.Alg
+	}
+	return ""
+}
+
+func (m *PublicKey) GetN() string {
+	if m != nil {
+		return m.N
+	}
+	return ""
+}
+
+func (m *PublicKey) GetE() string {
+	if m != nil {
+		return m.E
+	}
+	return ""
+}
+
+func (m *PublicKey) GetCrv() string {
+	if m != nil {
+		return m.Crv
+	}
+	return ""
+}
+
+func (m *PublicKey) GetX() string {
+	if m != nil {
+		return m.X
+	}
+	return ""
+}
+
+// GetPublicKeysResponse is a JWKS document when encoded as JSON
+type GetPublicKeysResponse struct {
+	Keys []*PublicKey `protobuf:"bytes,1,rep,name=keys" json:"keys,omitempty"`
+}
+
+func (m *GetPublicKeysResponse) Reset()                    { *m = GetPublicKeysResponse{} }
+func (m *GetPublicKeysResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetPublicKeysResponse) ProtoMessage()               {}
+func (*GetPublicKeysResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *GetPublicKeysResponse) GetKeys() []*PublicKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+// MfaChallenge is the detail of the FailedPrecondition that AuthenticateByEmail
+// and ConsumeLoginLink fail with when the account has MFA enabled, complete
+// the login by passing its token to VerifyMfa
+type MfaChallenge struct {
+	Token     string                      `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *MfaChallenge) Reset()                    { *m = MfaChallenge{} }
+func (m *MfaChallenge) String() string            { return proto.CompactTextString(m) }
+func (*MfaChallenge) ProtoMessage()               {}
+func (*MfaChallenge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *MfaChallenge) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *MfaChallenge) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+type EnrolMfaRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *EnrolMfaRequest) Reset()                    { *m = EnrolMfaRequest{} }
+func (m *EnrolMfaRequest) String() string            { return proto.CompactTextString(m) }
+func (*EnrolMfaRequest) ProtoMessage()               {}
+func (*EnrolMfaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *EnrolMfaRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type EnrolMfaResponse struct {
+	Secret string `protobuf:"bytes,1,opt,name=secret" json:"secret,omitempty"`
+	// otpauth:// URI for authenticator apps, usually shown as a QR code
+	Uri string `protobuf:"bytes,2,opt,name=uri" json:"uri,omitempty"`
+}
+
+func (m *EnrolMfaResponse) Reset()                    { *m = EnrolMfaResponse{} }
+func (m *EnrolMfaResponse) String() string            { return proto.CompactTextString(m) }
+func (*EnrolMfaResponse) ProtoMessage()               {}
+func (*EnrolMfaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *EnrolMfaResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EnrolMfaResponse) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+type ActivateMfaRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+	Code      string `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *ActivateMfaRequest) Reset()                    { *m = ActivateMfaRequest{} }
+func (m *ActivateMfaRequest) String() string            { return proto.CompactTextString(m) }
+func (*ActivateMfaRequest) ProtoMessage()               {}
+func (*ActivateMfaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *ActivateMfaRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *ActivateMfaRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type DisableMfaRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *DisableMfaRequest) Reset()                    { *m = DisableMfaRequest{} }
+func (m *DisableMfaRequest) String() string            { return proto.CompactTextString(m) }
+func (*DisableMfaRequest) ProtoMessage()               {}
+func (*DisableMfaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *DisableMfaRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type GenerateRecoveryCodesRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *GenerateRecoveryCodesRequest) Reset()                    { *m = GenerateRecoveryCodesRequest{} }
+func (m *GenerateRecoveryCodesRequest) String() string            { return proto.CompactTextString(m) }
+func (*GenerateRecoveryCodesRequest) ProtoMessage()               {}
+func (*GenerateRecoveryCodesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *GenerateRecoveryCodesRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type GenerateRecoveryCodesResponse struct {
+	Codes []string `protobuf:"bytes,1,rep,name=codes" json:"codes,omitempty"`
+}
+
+func (m *GenerateRecoveryCodesResponse) Reset()                    { *m = GenerateRecoveryCodesResponse{} }
+func (m *GenerateRecoveryCodesResponse) String() string            { return proto.CompactTextString(m) }
+func (*GenerateRecoveryCodesResponse) ProtoMessage()               {}
+func (*GenerateRecoveryCodesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *GenerateRecoveryCodesResponse) GetCodes() []string {
+	if m != nil {
+		return m.Codes
+	}
+	return nil
+}
+
+type VerifyMfaRequest struct {
+	// the token of an MfaChallenge
+	Challenge string `protobuf:"bytes,1,opt,name=challenge" json:"challenge,omitempty"`
+	// a TOTP or recovery code
+	Code string `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *VerifyMfaRequest) Reset()                    { *m = VerifyMfaRequest{} }
+func (m *VerifyMfaRequest) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMfaRequest) ProtoMessage()               {}
+func (*VerifyMfaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *VerifyMfaRequest) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *VerifyMfaRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type UnlockAccountRequest struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+}
+
+func (m *UnlockAccountRequest) Reset()                    { *m = UnlockAccountRequest{} }
+func (m *UnlockAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*UnlockAccountRequest) ProtoMessage()               {}
+func (*UnlockAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *UnlockAccountRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type RequestLoginLinkRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *RequestLoginLinkRequest) Reset()                    { *m = RequestLoginLinkRequest{} }
+func (m *RequestLoginLinkRequest) String() string            { return proto.CompactTextString(m) }
+func (*RequestLoginLinkRequest) ProtoMessage()               {}
+func (*RequestLoginLinkRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *RequestLoginLinkRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+// RequestLoginLinkResponse is published for the link to be emailed, token
+// is empty when the email isn't registered
+type RequestLoginLinkResponse struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	// single use, the link can't be used after expires_at
+	Token     string                      `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *RequestLoginLinkResponse) Reset()                    { *m = RequestLoginLinkResponse{} }
+func (m *RequestLoginLinkResponse) String() string            { return proto.CompactTextString(m) }
+func (*RequestLoginLinkResponse) ProtoMessage()               {}
+func (*RequestLoginLinkResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *RequestLoginLinkResponse) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *RequestLoginLinkResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *RequestLoginLinkResponse) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+type ConsumeLoginLinkRequest struct {
+	Token     string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	UserAgent string `protobuf:"bytes,2,opt,name=user_agent,json=userAgent" json:"user_agent,omitempty"`
+	Device    string `protobuf:"bytes,3,opt,name=device" json:"device,omitempty"`
+}
+
+func (m *ConsumeLoginLinkRequest) Reset()                    { *m = ConsumeLoginLinkRequest{} }
+func (m *ConsumeLoginLinkRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConsumeLoginLinkRequest) ProtoMessage()               {}
+func (*ConsumeLoginLinkRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *ConsumeLoginLinkRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ConsumeLoginLinkRequest) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *ConsumeLoginLinkRequest) GetDevice() string {
+	if m != nil {
+		return m.Device
+	}
+	return ""
+}
+
+// ConsumeLoginLinkResponse has the account and a new session for it
+type ConsumeLoginLinkResponse struct {
+	Account *Account `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	Session *Session `protobuf:"bytes,2,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *ConsumeLoginLinkResponse) Reset()                    { *m = ConsumeLoginLinkResponse{} }
+func (m *ConsumeLoginLinkResponse) String() string            { return proto.CompactTextString(m) }
+func (*ConsumeLoginLinkResponse) ProtoMessage()               {}
+func (*ConsumeLoginLinkResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *ConsumeLoginLinkResponse) GetAccount() *Account {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *ConsumeLoginLinkResponse) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type RequestEmailChangeRequest struct {
+	Id    string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *RequestEmailChangeRequest) Reset()                    { *m = RequestEmailChangeRequest{} }
+func (m *RequestEmailChangeRequest) String() string            { return proto.CompactTextString(m) }
+func (*RequestEmailChangeRequest) ProtoMessage()               {}
+func (*RequestEmailChangeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *RequestEmailChangeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RequestEmailChangeRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+// EmailChange is published when a change is requested, for the token to be
+// sent to the new email and a notice to the old one, and again once it's
+// confirmed
+type EmailChange struct {
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+	OldEmail  string `protobuf:"bytes,2,opt,name=old_email,json=oldEmail" json:"old_email,omitempty"`
+	NewEmail  string `protobuf:"bytes,3,opt,name=new_email,json=newEmail" json:"new_email,omitempty"`
+	// only set when the change is requested
+	Token     string                      `protobuf:"bytes,4,opt,name=token" json:"token,omitempty"`
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *EmailChange) Reset()                    { *m = EmailChange{} }
+func (m *EmailChange) String() string            { return proto.CompactTextString(m) }
+func (*EmailChange) ProtoMessage()               {}
+func (*EmailChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *EmailChange) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *EmailChange) GetOldEmail() string {
+	if m != nil {
+		return m.OldEmail
+	}
+	return ""
+}
+
+func (m *EmailChange) GetNewEmail() string {
+	if m != nil {
+		return m.NewEmail
+	}
+	return ""
+}
+
+func (m *EmailChange) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *EmailChange) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+}
+
+func (m *ConfirmEmailChangeRequest) Reset()                    { *m = ConfirmEmailChangeRequest{} }
+func (m *ConfirmEmailChangeRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConfirmEmailChangeRequest) ProtoMessage()               {}
+func (*ConfirmEmailChangeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *ConfirmEmailChangeRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ResendConfirmationRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *ResendConfirmationRequest) Reset()                    { *m = ResendConfirmationRequest{} }
+func (m *ResendConfirmationRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResendConfirmationRequest) ProtoMessage()               {}
+func (*ResendConfirmationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ResendConfirmationRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+// ResendConfirmationResponse is published for the new token to be emailed,
+// it's empty when the email isn't registered or already confirmed
+type ResendConfirmationResponse struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+	// the token can't be used to confirm the account after this time
+	ExpiresAt *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *ResendConfirmationResponse) Reset()                    { *m = ResendConfirmationResponse{} }
+func (m *ResendConfirmationResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResendConfirmationResponse) ProtoMessage()               {}
+func (*ResendConfirmationResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *ResendConfirmationResponse) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *ResendConfirmationResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ResendConfirmationResponse) GetExpiresAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+type ValidateSessionRequest struct {
+	AccessToken string `protobuf:"bytes,1,opt,name=access_token,json=accessToken" json:"access_token,omitempty"`
+}
+
+func (m *ValidateSessionRequest) Reset()                    { *m = ValidateSessionRequest{} }
+func (m *ValidateSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*ValidateSessionRequest) ProtoMessage()               {}
+func (*ValidateSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *ValidateSessionRequest) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+func init() {
+	proto.RegisterType((*Account)(nil), "account_service.Account")
+	proto.RegisterType((*ListAccountsRequest)(nil), "account_service.ListAccountsRequest")
+	proto.RegisterType((*ListAccountsResponse)(nil), "account_service.ListAccountsResponse")
+	proto.RegisterType((*GetByIdRequest)(nil), "account_service.GetByIdRequest")
+	proto.RegisterType((*GetByEmailRequest)(nil), "account_service.GetByEmailRequest")
+	proto.RegisterType((*AuthenticateByEmailRequest)(nil), "account_service.AuthenticateByEmailRequest")
+	proto.RegisterType((*GeneratePasswordTokenRequest)(nil), "account_service.GeneratePasswordTokenRequest")
+	proto.RegisterType((*GeneratePasswordTokenResponse)(nil), "account_service.GeneratePasswordTokenResponse")
+	proto.RegisterType((*ResetPasswordRequest)(nil), "account_service.ResetPasswordRequest")
+	proto.RegisterType((*ConfirmAccountRequest)(nil), "account_service.ConfirmAccountRequest")
+	proto.RegisterType((*CreateAccountRequest)(nil), "account_service.CreateAccountRequest")
+	proto.RegisterType((*UpdateAccountRequest)(nil), "account_service.UpdateAccountRequest")
+	proto.RegisterType((*DeleteAccountRequest)(nil), "account_service.DeleteAccountRequest")
+	proto.RegisterType((*Session)(nil), "account_service.Session")
+	proto.RegisterType((*CreateSessionRequest)(nil), "account_service.CreateSessionRequest")
+	proto.RegisterType((*RefreshSessionRequest)(nil), "account_service.RefreshSessionRequest")
+	proto.RegisterType((*RevokeSessionRequest)(nil), "account_service.RevokeSessionRequest")
+	proto.RegisterType((*ListSessionsRequest)(nil), "account_service.ListSessionsRequest")
+	proto.RegisterType((*ListSessionsResponse)(nil), "account_service.ListSessionsResponse")
+	proto.RegisterType((*RevokeAllSessionsRequest)(nil), "account_service.RevokeAllSessionsRequest")
+	proto.RegisterType((*IssueTokenRequest)(nil), "account_service.IssueTokenRequest")
+	proto.RegisterType((*IssueTokenResponse)(nil), "account_service.IssueTokenResponse")
+	proto.RegisterType((*GetPublicKeysRequest)(nil), "account_service.GetPublicKeysRequest")
+	proto.RegisterType((*PublicKey)(nil), "account_service.PublicKey")
+	proto.RegisterType((*GetPublicKeysResponse)(nil), "account_service.GetPublicKeysResponse")
+	proto.RegisterType((*MfaChallenge)(nil), "account_service.MfaChallenge")
+	proto.RegisterType((*EnrolMfaRequest)(nil), "account_service.EnrolMfaRequest")
+	proto.RegisterType((*EnrolMfaResponse)(nil), "account_service.EnrolMfaResponse")
+	proto.RegisterType((*ActivateMfaRequest)(nil), "account_service.ActivateMfaRequest")
+	proto.RegisterType((*DisableMfaRequest)(nil), "account_service.DisableMfaRequest")
+	proto.RegisterType((*GenerateRecoveryCodesRequest)(nil), "account_service.GenerateRecoveryCodesRequest")
+	proto.RegisterType((*GenerateRecoveryCodesResponse)(nil), "account_service.GenerateRecoveryCodesResponse")
+	proto.RegisterType((*VerifyMfaRequest)(nil), "account_service.VerifyMfaRequest")
+	proto.RegisterType((*UnlockAccountRequest)(nil), "account_service.UnlockAccountRequest")
+	proto.RegisterType((*RequestLoginLinkRequest)(nil), "account_service.RequestLoginLinkRequest")
+	proto.RegisterType((*RequestLoginLinkResponse)(nil), "account_service.RequestLoginLinkResponse")
+	proto.RegisterType((*ConsumeLoginLinkRequest)(nil), "account_service.ConsumeLoginLinkRequest")
+	proto.RegisterType((*ConsumeLoginLinkResponse)(nil), "account_service.ConsumeLoginLinkResponse")
+	proto.RegisterType((*RequestEmailChangeRequest)(nil), "account_service.RequestEmailChangeRequest")
+	proto.RegisterType((*EmailChange)(nil), "account_service.EmailChange")
+	proto.RegisterType((*ConfirmEmailChangeRequest)(nil), "account_service.ConfirmEmailChangeRequest")
+	proto.RegisterType((*ResendConfirmationRequest)(nil), "account_service.ResendConfirmationRequest")
+	proto.RegisterType((*ResendConfirmationResponse)(nil), "account_service.ResendConfirmationResponse")
+	proto.RegisterType((*ValidateSessionRequest)(nil), "account_service.ValidateSessionRequest")
+	proto.RegisterEnum("account_service.ConfirmedFilter", ConfirmedFilter_name, ConfirmedFilter_value)
+	proto.RegisterEnum("account_service.AccountView", AccountView_name, AccountView_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for AccountService service
+
+type AccountServiceClient interface {
+	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Account, error)
+	GetByEmail(ctx context.Context, in *GetByEmailRequest, opts ...grpc.CallOption) (*Account, error)
+	AuthenticateByEmail(ctx context.Context, in *AuthenticateByEmailRequest, opts ...grpc.CallOption) (*Account, error)
+	GeneratePasswordToken(ctx context.Context, in *GeneratePasswordTokenRequest, opts ...grpc.CallOption) (*GeneratePasswordTokenResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Account, error)
+	ConfirmAccount(ctx context.Context, in *ConfirmAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	RefreshSession(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	RevokeAllSessions(ctx context.Context, in *RevokeAllSessionsRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	IssueToken(ctx context.Context, in *IssueTokenRequest, opts ...grpc.CallOption) (*IssueTokenResponse, error)
+	GetPublicKeys(ctx context.Context, in *GetPublicKeysRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error)
+	EnrolMfa(ctx context.Context, in *EnrolMfaRequest, opts ...grpc.CallOption) (*EnrolMfaResponse, error)
+	ActivateMfa(ctx context.Context, in *ActivateMfaRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	DisableMfa(ctx context.Context, in *DisableMfaRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	GenerateRecoveryCodes(ctx context.Context, in *GenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*GenerateRecoveryCodesResponse, error)
+	VerifyMfa(ctx context.Context, in *VerifyMfaRequest, opts ...grpc.CallOption) (*Account, error)
+	UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	RequestLoginLink(ctx context.Context, in *RequestLoginLinkRequest, opts ...grpc.CallOption) (*RequestLoginLinkResponse, error)
+	ConsumeLoginLink(ctx context.Context, in *ConsumeLoginLinkRequest, opts ...grpc.CallOption) (*ConsumeLoginLinkResponse, error)
+	RequestEmailChange(ctx context.Context, in *RequestEmailChangeRequest, opts ...grpc.CallOption) (*EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeRequest, opts ...grpc.CallOption) (*EmailChange, error)
+	ResendConfirmation(ctx context.Context, in *ResendConfirmationRequest, opts ...grpc.CallOption) (*ResendConfirmationResponse, error)
+	ValidateSession(ctx context.Context, in *ValidateSessionRequest, opts ...grpc.CallOption) (*Session, error)
+}
+
+type accountServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAccountServiceClient(cc *grpc.ClientConn) AccountServiceClient {
+	return &accountServiceClient{cc}
+}
+
+func (c *accountServiceClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/GetById", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetByEmail(ctx context.Context, in *GetByEmailRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/GetByEmail", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) AuthenticateByEmail(ctx context.Context, in *AuthenticateByEmailRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/AuthenticateByEmail", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GeneratePasswordToken(ctx context.Context, in *GeneratePasswordTokenRequest, opts ...grpc.CallOption) (*GeneratePasswordTokenResponse, error) {
+	out := new(GeneratePasswordTokenResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/GeneratePasswordToken", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ResetPassword", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ConfirmAccount(ctx context.Context, in *ConfirmAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ConfirmAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) Update(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/Update", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/CreateSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RefreshSession(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/RefreshSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/RevokeSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ListSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RevokeAllSessions(ctx context.Context, in *RevokeAllSessionsRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/RevokeAllSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) IssueToken(ctx context.Context, in *IssueTokenRequest, opts ...grpc.CallOption) (*IssueTokenResponse, error) {
+	out := new(IssueTokenResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/IssueToken", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetPublicKeys(ctx context.Context, in *GetPublicKeysRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error) {
+	out := new(GetPublicKeysResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/GetPublicKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) EnrolMfa(ctx context.Context, in *EnrolMfaRequest, opts ...grpc.CallOption) (*EnrolMfaResponse, error) {
+	out := new(EnrolMfaResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/EnrolMfa", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ActivateMfa(ctx context.Context, in *ActivateMfaRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ActivateMfa", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) DisableMfa(ctx context.Context, in *DisableMfaRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/DisableMfa", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GenerateRecoveryCodes(ctx context.Context, in *GenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*GenerateRecoveryCodesResponse, error) {
+	out := new(GenerateRecoveryCodesResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/GenerateRecoveryCodes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) VerifyMfa(ctx context.Context, in *VerifyMfaRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/VerifyMfa", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/UnlockAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RequestLoginLink(ctx context.Context, in *RequestLoginLinkRequest, opts ...grpc.CallOption) (*RequestLoginLinkResponse, error) {
+	out := new(RequestLoginLinkResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/RequestLoginLink", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ConsumeLoginLink(ctx context.Context, in *ConsumeLoginLinkRequest, opts ...grpc.CallOption) (*ConsumeLoginLinkResponse, error) {
+	out := new(ConsumeLoginLinkResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ConsumeLoginLink", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RequestEmailChange(ctx context.Context, in *RequestEmailChangeRequest, opts ...grpc.CallOption) (*EmailChange, error) {
+	out := new(EmailChange)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/RequestEmailChange", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeRequest, opts ...grpc.CallOption) (*EmailChange, error) {
+	out := new(EmailChange)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ConfirmEmailChange", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ResendConfirmation(ctx context.Context, in *ResendConfirmationRequest, opts ...grpc.CallOption) (*ResendConfirmationResponse, error) {
+	out := new(ResendConfirmationResponse)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ResendConfirmation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ValidateSession(ctx context.Context, in *ValidateSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := grpc.Invoke(ctx, "/account_service.AccountService/ValidateSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for AccountService service
+
 type AccountServiceServer interface {
 	List(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
 	GetById(context.Context, *GetByIdRequest) (*Account, error)
@@ -525,188 +1851,549 @@ type AccountServiceServer interface {
 	Create(context.Context, *CreateAccountRequest) (*Account, error)
 	Update(context.Context, *UpdateAccountRequest) (*Account, error)
 	Delete(context.Context, *DeleteAccountRequest) (*google_protobuf.Empty, error)
+	CreateSession(context.Context, *CreateSessionRequest) (*Session, error)
+	RefreshSession(context.Context, *RefreshSessionRequest) (*Session, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*google_protobuf.Empty, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	RevokeAllSessions(context.Context, *RevokeAllSessionsRequest) (*google_protobuf.Empty, error)
+	IssueToken(context.Context, *IssueTokenRequest) (*IssueTokenResponse, error)
+	GetPublicKeys(context.Context, *GetPublicKeysRequest) (*GetPublicKeysResponse, error)
+	EnrolMfa(context.Context, *EnrolMfaRequest) (*EnrolMfaResponse, error)
+	ActivateMfa(context.Context, *ActivateMfaRequest) (*google_protobuf.Empty, error)
+	DisableMfa(context.Context, *DisableMfaRequest) (*google_protobuf.Empty, error)
+	GenerateRecoveryCodes(context.Context, *GenerateRecoveryCodesRequest) (*GenerateRecoveryCodesResponse, error)
+	VerifyMfa(context.Context, *VerifyMfaRequest) (*Account, error)
+	UnlockAccount(context.Context, *UnlockAccountRequest) (*google_protobuf.Empty, error)
+	RequestLoginLink(context.Context, *RequestLoginLinkRequest) (*RequestLoginLinkResponse, error)
+	ConsumeLoginLink(context.Context, *ConsumeLoginLinkRequest) (*ConsumeLoginLinkResponse, error)
+	RequestEmailChange(context.Context, *RequestEmailChangeRequest) (*EmailChange, error)
+	ConfirmEmailChange(context.Context, *ConfirmEmailChangeRequest) (*EmailChange, error)
+	ResendConfirmation(context.Context, *ResendConfirmationRequest) (*ResendConfirmationResponse, error)
+	ValidateSession(context.Context, *ValidateSessionRequest) (*Session, error)
+}
+
+func RegisterAccountServiceServer(s *grpc.Server, srv AccountServiceServer) {
+	s.RegisterService(&_AccountService_serviceDesc, srv)
+}
+
+func _AccountService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).List(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GetById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/GetById",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetById(ctx, req.(*GetByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GetByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetByEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/GetByEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetByEmail(ctx, req.(*GetByEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_AuthenticateByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateByEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).AuthenticateByEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/AuthenticateByEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).AuthenticateByEmail(ctx, req.(*AuthenticateByEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GeneratePasswordToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeneratePasswordTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GeneratePasswordToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/GeneratePasswordToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GeneratePasswordToken(ctx, req.(*GeneratePasswordTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ConfirmAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ConfirmAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ConfirmAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ConfirmAccount(ctx, req.(*ConfirmAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).Create(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterAccountServiceServer(s *grpc.Server, srv AccountServiceServer) {
-	s.RegisterService(&_AccountService_serviceDesc, srv)
+func _AccountService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).Update(ctx, req.(*UpdateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAccountsRequest)
+func _AccountService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).List(ctx, in)
+		return srv.(AccountServiceServer).Delete(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/List",
+		FullMethod: "/account_service.AccountService/Delete",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).List(ctx, req.(*ListAccountsRequest))
+		return srv.(AccountServiceServer).Delete(ctx, req.(*DeleteAccountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_GetById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetByIdRequest)
+func _AccountService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).GetById(ctx, in)
+		return srv.(AccountServiceServer).CreateSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/GetById",
+		FullMethod: "/account_service.AccountService/CreateSession",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).GetById(ctx, req.(*GetByIdRequest))
+		return srv.(AccountServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_GetByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetByEmailRequest)
+func _AccountService_RefreshSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).GetByEmail(ctx, in)
+		return srv.(AccountServiceServer).RefreshSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/GetByEmail",
+		FullMethod: "/account_service.AccountService/RefreshSession",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).GetByEmail(ctx, req.(*GetByEmailRequest))
+		return srv.(AccountServiceServer).RefreshSession(ctx, req.(*RefreshSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_AuthenticateByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthenticateByEmailRequest)
+func _AccountService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).AuthenticateByEmail(ctx, in)
+		return srv.(AccountServiceServer).RevokeSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/AuthenticateByEmail",
+		FullMethod: "/account_service.AccountService/RevokeSession",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).AuthenticateByEmail(ctx, req.(*AuthenticateByEmailRequest))
+		return srv.(AccountServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_GeneratePasswordToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GeneratePasswordTokenRequest)
+func _AccountService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).GeneratePasswordToken(ctx, in)
+		return srv.(AccountServiceServer).ListSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/GeneratePasswordToken",
+		FullMethod: "/account_service.AccountService/ListSessions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).GeneratePasswordToken(ctx, req.(*GeneratePasswordTokenRequest))
+		return srv.(AccountServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ResetPasswordRequest)
+func _AccountService_RevokeAllSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllSessionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).ResetPassword(ctx, in)
+		return srv.(AccountServiceServer).RevokeAllSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/ResetPassword",
+		FullMethod: "/account_service.AccountService/RevokeAllSessions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+		return srv.(AccountServiceServer).RevokeAllSessions(ctx, req.(*RevokeAllSessionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_ConfirmAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConfirmAccountRequest)
+func _AccountService_IssueToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).ConfirmAccount(ctx, in)
+		return srv.(AccountServiceServer).IssueToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/ConfirmAccount",
+		FullMethod: "/account_service.AccountService/IssueToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).ConfirmAccount(ctx, req.(*ConfirmAccountRequest))
+		return srv.(AccountServiceServer).IssueToken(ctx, req.(*IssueTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateAccountRequest)
+func _AccountService_GetPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).Create(ctx, in)
+		return srv.(AccountServiceServer).GetPublicKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/Create",
+		FullMethod: "/account_service.AccountService/GetPublicKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).Create(ctx, req.(*CreateAccountRequest))
+		return srv.(AccountServiceServer).GetPublicKeys(ctx, req.(*GetPublicKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateAccountRequest)
+func _AccountService_EnrolMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrolMfaRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).Update(ctx, in)
+		return srv.(AccountServiceServer).EnrolMfa(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/Update",
+		FullMethod: "/account_service.AccountService/EnrolMfa",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).Update(ctx, req.(*UpdateAccountRequest))
+		return srv.(AccountServiceServer).EnrolMfa(ctx, req.(*EnrolMfaRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AccountService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAccountRequest)
+func _AccountService_ActivateMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateMfaRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountServiceServer).Delete(ctx, in)
+		return srv.(AccountServiceServer).ActivateMfa(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/account_service.AccountService/Delete",
+		FullMethod: "/account_service.AccountService/ActivateMfa",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountServiceServer).Delete(ctx, req.(*DeleteAccountRequest))
+		return srv.(AccountServiceServer).ActivateMfa(ctx, req.(*ActivateMfaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_DisableMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableMfaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).DisableMfa(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/DisableMfa",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).DisableMfa(ctx, req.(*DisableMfaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRecoveryCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/GenerateRecoveryCodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GenerateRecoveryCodes(ctx, req.(*GenerateRecoveryCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_VerifyMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMfaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).VerifyMfa(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/VerifyMfa",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).VerifyMfa(ctx, req.(*VerifyMfaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_UnlockAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).UnlockAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/UnlockAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).UnlockAccount(ctx, req.(*UnlockAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_RequestLoginLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestLoginLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).RequestLoginLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/RequestLoginLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).RequestLoginLink(ctx, req.(*RequestLoginLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ConsumeLoginLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumeLoginLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ConsumeLoginLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ConsumeLoginLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ConsumeLoginLink(ctx, req.(*ConsumeLoginLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_RequestEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).RequestEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/RequestEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).RequestEmailChange(ctx, req.(*RequestEmailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ConfirmEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmEmailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ConfirmEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ConfirmEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ConfirmEmailChange(ctx, req.(*ConfirmEmailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ResendConfirmation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendConfirmationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ResendConfirmation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ResendConfirmation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ResendConfirmation(ctx, req.(*ResendConfirmationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ValidateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ValidateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/account_service.AccountService/ValidateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ValidateSession(ctx, req.(*ValidateSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -755,6 +2442,82 @@ var _AccountService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _AccountService_Delete_Handler,
 		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _AccountService_CreateSession_Handler,
+		},
+		{
+			MethodName: "RefreshSession",
+			Handler:    _AccountService_RefreshSession_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _AccountService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _AccountService_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeAllSessions",
+			Handler:    _AccountService_RevokeAllSessions_Handler,
+		},
+		{
+			MethodName: "IssueToken",
+			Handler:    _AccountService_IssueToken_Handler,
+		},
+		{
+			MethodName: "GetPublicKeys",
+			Handler:    _AccountService_GetPublicKeys_Handler,
+		},
+		{
+			MethodName: "EnrolMfa",
+			Handler:    _AccountService_EnrolMfa_Handler,
+		},
+		{
+			MethodName: "ActivateMfa",
+			Handler:    _AccountService_ActivateMfa_Handler,
+		},
+		{
+			MethodName: "DisableMfa",
+			Handler:    _AccountService_DisableMfa_Handler,
+		},
+		{
+			MethodName: "GenerateRecoveryCodes",
+			Handler:    _AccountService_GenerateRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "VerifyMfa",
+			Handler:    _AccountService_VerifyMfa_Handler,
+		},
+		{
+			MethodName: "UnlockAccount",
+			Handler:    _AccountService_UnlockAccount_Handler,
+		},
+		{
+			MethodName: "RequestLoginLink",
+			Handler:    _AccountService_RequestLoginLink_Handler,
+		},
+		{
+			MethodName: "ConsumeLoginLink",
+			Handler:    _AccountService_ConsumeLoginLink_Handler,
+		},
+		{
+			MethodName: "RequestEmailChange",
+			Handler:    _AccountService_RequestEmailChange_Handler,
+		},
+		{
+			MethodName: "ConfirmEmailChange",
+			Handler:    _AccountService_ConfirmEmailChange_Handler,
+		},
+		{
+			MethodName: "ResendConfirmation",
+			Handler:    _AccountService_ResendConfirmation_Handler,
+		},
+		{
+			MethodName: "ValidateSession",
+			Handler:    _AccountService_ValidateSession_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "account_service.proto",
@@ -763,53 +2526,146 @@ var _AccountService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("account_service.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 767 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xdd, 0x52, 0xd3, 0x40,
-	0x14, 0xa6, 0xff, 0xe5, 0xd4, 0x16, 0x5d, 0x82, 0x93, 0x09, 0x32, 0xd6, 0xa0, 0x0c, 0xea, 0x90,
-	0x3a, 0x05, 0x1d, 0x47, 0xbd, 0x01, 0x44, 0x64, 0x44, 0xc5, 0x00, 0x37, 0x5e, 0xd8, 0x09, 0xed,
-	0xa1, 0x64, 0x68, 0x93, 0x9a, 0x6c, 0x91, 0xf2, 0x30, 0x3e, 0x80, 0x17, 0x3e, 0x96, 0xcf, 0xe1,
-	0x64, 0x77, 0x13, 0x9b, 0x66, 0x1b, 0xb8, 0xf1, 0xae, 0x39, 0xfb, 0x9d, 0xef, 0x7c, 0x7b, 0xf6,
-	0x7c, 0xdb, 0x85, 0x05, 0xab, 0xdd, 0x76, 0x87, 0x0e, 0x6d, 0xf9, 0xe8, 0x5d, 0xd8, 0x6d, 0x34,
-	0x06, 0x9e, 0x4b, 0x5d, 0x32, 0x37, 0x11, 0xd6, 0x16, 0xbb, 0xae, 0xdb, 0xed, 0x61, 0x83, 0x2d,
-	0x9f, 0x0c, 0x4f, 0x1b, 0xd8, 0x1f, 0xd0, 0x11, 0x47, 0x6b, 0xeb, 0x5d, 0x9b, 0x9e, 0x0d, 0x4f,
-	0x8c, 0xb6, 0xdb, 0x6f, 0xf4, 0xec, 0x1e, 0xda, 0x6e, 0xc3, 0xee, 0x5b, 0x5d, 0x0c, 0xb3, 0xe3,
-	0x5f, 0x3c, 0x49, 0xff, 0x95, 0x83, 0xd2, 0x26, 0xaf, 0x42, 0x6a, 0x90, 0xb5, 0x3b, 0x6a, 0xa6,
-	0x9e, 0x59, 0x9d, 0x35, 0xb3, 0x76, 0x87, 0x10, 0xc8, 0x3b, 0x56, 0x1f, 0xd5, 0x2c, 0x8b, 0xb0,
-	0xdf, 0x44, 0x81, 0x02, 0xf6, 0x2d, 0xbb, 0xa7, 0xe6, 0x58, 0x90, 0x7f, 0x90, 0x37, 0x50, 0x64,
-	0xe4, 0xbe, 0x9a, 0xaf, 0xe7, 0x56, 0x2b, 0xcd, 0x87, 0xc6, 0xe4, 0x86, 0x44, 0x0d, 0x63, 0x8f,
-	0xc1, 0x76, 0x1c, 0xea, 0x8d, 0x4c, 0x91, 0x43, 0x96, 0xa1, 0xda, 0x76, 0x9d, 0x53, 0xdb, 0xeb,
-	0xb7, 0xa8, 0x7b, 0x8e, 0x8e, 0x5a, 0x60, 0xdc, 0xb7, 0x44, 0xf0, 0x28, 0x88, 0x91, 0x67, 0xa0,
-	0x0c, 0x2c, 0xdf, 0xff, 0xe1, 0x7a, 0x9d, 0x96, 0x87, 0x3e, 0x52, 0x81, 0x2d, 0x32, 0x2c, 0x09,
-	0xd7, 0xcc, 0x60, 0x89, 0x67, 0x6c, 0x41, 0xb9, 0x8f, 0xd4, 0xea, 0x58, 0xd4, 0x52, 0x4b, 0x4c,
-	0xd6, 0xca, 0x54, 0x59, 0x1f, 0x05, 0x90, 0x0b, 0x8b, 0xf2, 0xb4, 0xcf, 0x50, 0x19, 0x53, 0x4c,
-	0x6e, 0x43, 0xee, 0x1c, 0x47, 0xa2, 0x45, 0xc1, 0x4f, 0xf2, 0x04, 0x0a, 0x17, 0x56, 0x6f, 0xc8,
-	0x9b, 0x54, 0x69, 0x2a, 0x46, 0xbc, 0xc9, 0x2c, 0xd9, 0xe4, 0x90, 0x57, 0xd9, 0x97, 0x19, 0xed,
-	0x35, 0x54, 0x63, 0xb5, 0x24, 0x94, 0xca, 0x38, 0xe5, 0xec, 0x58, 0xb2, 0xfe, 0x05, 0xe6, 0xf7,
-	0x6d, 0x9f, 0x0a, 0xd1, 0xbe, 0x89, 0xdf, 0x87, 0xe8, 0x53, 0xb2, 0x08, 0xb3, 0x03, 0x56, 0xd4,
-	0xbe, 0x42, 0x46, 0x54, 0x30, 0xcb, 0x41, 0xe0, 0xd0, 0xbe, 0x42, 0xb2, 0x04, 0xc0, 0x16, 0x79,
-	0xb7, 0x38, 0x25, 0x83, 0xb3, 0x26, 0xe9, 0x14, 0x94, 0x38, 0xa5, 0x3f, 0x70, 0x1d, 0x1f, 0xc9,
-	0x06, 0x94, 0x45, 0xaf, 0x7c, 0x35, 0xc3, 0x9a, 0xa7, 0x4e, 0x6b, 0x9e, 0x19, 0x21, 0xc9, 0x0a,
-	0xcc, 0x39, 0x78, 0x49, 0x5b, 0x89, 0x8a, 0xd5, 0x20, 0x7c, 0x10, 0x55, 0xad, 0x43, 0x6d, 0x17,
-	0xe9, 0xd6, 0x68, 0xaf, 0x13, 0xee, 0x61, 0x62, 0xf6, 0xf4, 0xc7, 0x70, 0x87, 0x21, 0x76, 0x82,
-	0xf9, 0x0a, 0x41, 0xd1, 0xf0, 0x65, 0xc6, 0x86, 0x4f, 0xff, 0x04, 0xda, 0xe6, 0x90, 0x9e, 0xa1,
-	0x43, 0xed, 0xb6, 0x45, 0xf1, 0x26, 0x39, 0x44, 0x83, 0x72, 0x38, 0x31, 0x42, 0x61, 0xf4, 0xad,
-	0x6f, 0xc0, 0xbd, 0x5d, 0x74, 0xd0, 0xb3, 0x28, 0x1e, 0x88, 0x18, 0x53, 0x9d, 0xae, 0xe2, 0x39,
-	0x2c, 0x4d, 0xc9, 0x12, 0x1d, 0x55, 0xa0, 0xc0, 0x3b, 0x22, 0xd2, 0xd8, 0x87, 0xfe, 0x1e, 0x14,
-	0x36, 0xb2, 0x07, 0xd1, 0xfc, 0x46, 0x45, 0x92, 0xe8, 0x54, 0xd9, 0x6b, 0xb0, 0xb0, 0xcd, 0x0d,
-	0x13, 0x9e, 0x4b, 0x1a, 0x95, 0xfe, 0x33, 0x03, 0xca, 0xb6, 0x87, 0x16, 0xc5, 0x09, 0x78, 0x13,
-	0x4a, 0xe2, 0x3c, 0x59, 0x42, 0xda, 0xc1, 0x87, 0xc0, 0x34, 0x5d, 0xe4, 0x05, 0x14, 0x98, 0x27,
-	0xd8, 0x8d, 0x51, 0x69, 0xd6, 0x65, 0x0e, 0x39, 0xa4, 0xae, 0x87, 0x42, 0x80, 0xc9, 0xe1, 0xfa,
-	0xef, 0x0c, 0x28, 0xc7, 0x83, 0x4e, 0x52, 0xe0, 0xe4, 0x35, 0xf5, 0x1f, 0x8a, 0x8f, 0x37, 0x21,
-	0x7f, 0xc3, 0x26, 0xe8, 0x2b, 0xa0, 0xbc, 0xc5, 0x1e, 0x5e, 0xa7, 0xb7, 0xf9, 0xa7, 0x08, 0x35,
-	0x01, 0x39, 0xe4, 0x5c, 0xe4, 0x18, 0xf2, 0x81, 0x0b, 0x49, 0xf2, 0xde, 0x94, 0xf8, 0x5d, 0x7b,
-	0x74, 0x0d, 0x8a, 0x0f, 0x9c, 0x3e, 0x43, 0xde, 0x41, 0x49, 0xd8, 0x8c, 0xdc, 0x4f, 0xe4, 0xc4,
-	0x0d, 0xa8, 0x4d, 0xdd, 0xa0, 0x3e, 0x43, 0xf6, 0x01, 0xfe, 0x99, 0x91, 0xe8, 0x72, 0xaa, 0x71,
-	0xd7, 0xa5, 0xb2, 0x7d, 0x83, 0x79, 0x89, 0x5f, 0xc9, 0xd3, 0x64, 0xca, 0x54, 0x57, 0xa7, 0xf2,
-	0x5f, 0xc2, 0x82, 0xd4, 0x89, 0x64, 0x4d, 0x22, 0x7c, 0xba, 0xcf, 0x35, 0xe3, 0xa6, 0xf0, 0xa8,
-	0xdf, 0x26, 0x54, 0x63, 0x66, 0x26, 0xc9, 0x93, 0x92, 0x99, 0x3d, 0x75, 0x37, 0x47, 0x50, 0x8b,
-	0xdb, 0x9a, 0x24, 0xff, 0xc5, 0xa4, 0xbe, 0x4f, 0x65, 0xfd, 0x00, 0x45, 0x6e, 0x7e, 0x89, 0x44,
-	0xd9, 0xad, 0x70, 0x1d, 0x19, 0x37, 0xaa, 0x84, 0x4c, 0xe6, 0xe0, 0x54, 0xb2, 0x3d, 0x28, 0x72,
-	0x17, 0x49, 0xc8, 0x64, 0xf6, 0xd2, 0xee, 0x1a, 0xfc, 0x51, 0x64, 0x84, 0x8f, 0x22, 0x63, 0x27,
-	0x78, 0x14, 0xe9, 0x33, 0x5b, 0xcb, 0x5f, 0x1f, 0x24, 0x9f, 0x44, 0x13, 0x9c, 0x27, 0x45, 0x96,
-	0xb6, 0xfe, 0x37, 0x00, 0x00, 0xff, 0xff, 0x2c, 0x08, 0x71, 0x24, 0x83, 0x09, 0x00, 0x00,
+	// 2245 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0x5b, 0x77, 0xdb, 0xc6,
+	0x11, 0x36, 0x28, 0x8a, 0x97, 0x21, 0x29, 0xd1, 0x1b, 0x4a, 0x86, 0x60, 0xab, 0xa1, 0xa1, 0x44,
+	0x95, 0x9d, 0x9a, 0x72, 0x18, 0xa7, 0xa7, 0xae, 0x9d, 0xb4, 0xd4, 0x35, 0x72, 0x24, 0x45, 0x81,
+	0x2c, 0xf7, 0x76, 0x5a, 0x06, 0x22, 0x97, 0x12, 0x8e, 0x40, 0x80, 0x05, 0x96, 0x92, 0x98, 0xb7,
+	0xb6, 0xa7, 0xcf, 0x7d, 0xef, 0x43, 0xff, 0x46, 0xde, 0xfa, 0x2f, 0x7a, 0x4e, 0x7f, 0x4e, 0xcf,
+	0x2e, 0x16, 0x20, 0xee, 0xa0, 0xd2, 0xa6, 0x6f, 0xc0, 0xe0, 0x9b, 0xd9, 0xd9, 0xd9, 0xb9, 0x2e,
+	0x09, 0x4b, 0x6a, 0xaf, 0x67, 0x8e, 0x0d, 0xd2, 0xb5, 0xb1, 0x75, 0xad, 0xf5, 0x70, 0x6b, 0x64,
+	0x99, 0xc4, 0x44, 0x8b, 0x21, 0xb2, 0xf4, 0xf0, 0xc2, 0x34, 0x2f, 0x74, 0xbc, 0xc9, 0x3e, 0x9f,
+	0x8f, 0x07, 0x9b, 0x78, 0x38, 0x22, 0x13, 0x07, 0x2d, 0x7d, 0x72, 0xa1, 0x91, 0xcb, 0xf1, 0x79,
+	0xab, 0x67, 0x0e, 0x37, 0x75, 0x4d, 0xc7, 0x9a, 0xb9, 0xa9, 0x0d, 0xd5, 0x0b, 0xec, 0x72, 0x07,
+	0xdf, 0x38, 0xd3, 0xfb, 0x61, 0x89, 0x44, 0x1b, 0x62, 0x9b, 0xa8, 0xc3, 0x11, 0x07, 0x34, 0xc3,
+	0x80, 0x81, 0x86, 0xf5, 0x7e, 0x77, 0xa8, 0xda, 0x57, 0x0e, 0x42, 0xfe, 0x57, 0x01, 0x8a, 0x1d,
+	0x47, 0x51, 0xb4, 0x00, 0x39, 0xad, 0x2f, 0x0a, 0x4d, 0x61, 0xa3, 0xac, 0xe4, 0xb4, 0x3e, 0x42,
+	0x90, 0x37, 0xd4, 0x21, 0x16, 0x73, 0x8c, 0xc2, 0x9e, 0x51, 0x03, 0xe6, 0xf1, 0x50, 0xd5, 0x74,
+	0x71, 0x8e, 0x11, 0x9d, 0x17, 0xf4, 0x1a, 0x0a, 0x4c, 0x3f, 0x5b, 0xcc, 0x37, 0xe7, 0x36, 0x2a,
+	0xed, 0x0f, 0x5a, 0x61, 0x9b, 0xf0, 0x35, 0x5a, 0x07, 0x0c, 0xb6, 0x6b, 0x10, 0x6b, 0xa2, 0x70,
+	0x1e, 0xb4, 0x06, 0xb5, 0x9e, 0x69, 0x0c, 0x34, 0x6b, 0xd8, 0x25, 0xe6, 0x15, 0x36, 0xc4, 0x79,
+	0x26, 0xbb, 0xca, 0x89, 0x6f, 0x29, 0x0d, 0x3d, 0x87, 0xc6, 0x48, 0xb5, 0xed, 0x1b, 0xd3, 0xea,
+	0x77, 0x2d, 0x6c, 0x63, 0xc2, 0xb1, 0x05, 0x86, 0x45, 0xee, 0x37, 0x85, 0x7e, 0x72, 0x38, 0xb6,
+	0xa0, 0x34, 0xc4, 0x44, 0xed, 0xab, 0x44, 0x15, 0x8b, 0x4c, 0xad, 0xf5, 0x44, 0xb5, 0x8e, 0x38,
+	0xd0, 0x51, 0xcc, 0xe3, 0x43, 0x22, 0x14, 0xaf, 0xb1, 0x65, 0x6b, 0xa6, 0x21, 0x96, 0x9a, 0xc2,
+	0xc6, 0x9c, 0xe2, 0xbe, 0xa2, 0x9f, 0x00, 0x0a, 0x28, 0xdd, 0xbd, 0x54, 0xed, 0x4b, 0xb1, 0xcc,
+	0xb4, 0xa9, 0xfb, 0x35, 0xff, 0x42, 0xb5, 0x2f, 0xd1, 0x4b, 0x58, 0x89, 0xd3, 0xde, 0x61, 0x02,
+	0xc6, 0xb4, 0x1c, 0xdd, 0x02, 0x63, 0x7d, 0x04, 0x65, 0x2e, 0x0e, 0xf7, 0xc5, 0x6a, 0x53, 0xd8,
+	0x28, 0x29, 0x53, 0x02, 0x7a, 0x09, 0xd0, 0xb3, 0xb0, 0x4a, 0x70, 0xbf, 0xab, 0x12, 0xb1, 0xd6,
+	0x14, 0x36, 0x2a, 0x6d, 0xa9, 0xe5, 0x1c, 0x7b, 0xcb, 0x3d, 0xf6, 0xd6, 0x5b, 0xd7, 0x2f, 0x94,
+	0x32, 0x47, 0x77, 0x08, 0x65, 0x1d, 0x8f, 0xfa, 0x2e, 0xeb, 0x42, 0x36, 0x2b, 0x47, 0x77, 0x08,
+	0xfa, 0x0c, 0xaa, 0x9e, 0x0a, 0x94, 0x79, 0x31, 0x93, 0xb9, 0xe2, 0xe1, 0x3b, 0x04, 0x7d, 0x0e,
+	0x35, 0x5d, 0xb5, 0x49, 0x57, 0x37, 0x2f, 0x34, 0x83, 0xf2, 0xd7, 0xb3, 0xf9, 0x29, 0xc3, 0x21,
+	0xc5, 0x77, 0x88, 0xf4, 0x15, 0x54, 0x7c, 0x7e, 0x84, 0xea, 0x30, 0x77, 0x85, 0x27, 0xdc, 0x71,
+	0xe9, 0x23, 0x7a, 0x0a, 0xf3, 0xd7, 0xaa, 0x3e, 0x76, 0x5c, 0xb7, 0xd2, 0x6e, 0xb4, 0x82, 0xd1,
+	0xc3, 0x98, 0x15, 0x07, 0xf2, 0xf3, 0xdc, 0xcf, 0x04, 0xe9, 0x15, 0xd4, 0x02, 0x1e, 0x10, 0x23,
+	0xb2, 0xe1, 0x17, 0x59, 0xf6, 0x31, 0xbf, 0xc9, 0x97, 0x2a, 0xf5, 0xaa, 0x52, 0x1b, 0x0e, 0xd4,
+	0x6e, 0xef, 0x52, 0xd5, 0x75, 0x6c, 0x5c, 0x60, 0xf9, 0xbb, 0x3c, 0xbc, 0x77, 0xa8, 0xd9, 0x84,
+	0x3b, 0x98, 0xad, 0xe0, 0x3f, 0x8e, 0xb1, 0x4d, 0xd0, 0x43, 0x28, 0x8f, 0x98, 0x2a, 0xda, 0xb7,
+	0x98, 0x89, 0x9f, 0x57, 0x4a, 0x94, 0x70, 0xaa, 0x7d, 0x8b, 0xd1, 0x2a, 0x00, 0xfb, 0xe8, 0x78,
+	0xb6, 0xb3, 0x10, 0x83, 0x3b, 0x0e, 0xfd, 0x18, 0xaa, 0x2c, 0xdc, 0xba, 0x23, 0x0b, 0x0f, 0xb4,
+	0x5b, 0x1e, 0x82, 0x15, 0x46, 0x3b, 0x61, 0x24, 0x1a, 0x4a, 0x34, 0x4c, 0xbb, 0x3d, 0xd3, 0x20,
+	0xaa, 0x66, 0xd0, 0x78, 0x64, 0xa1, 0x44, 0x89, 0xdb, 0x9c, 0x86, 0x7e, 0x01, 0x35, 0xcf, 0x67,
+	0x06, 0x04, 0x5b, 0xe2, 0x7c, 0xa6, 0xf9, 0xab, 0xae, 0xdb, 0x50, 0x3c, 0xea, 0xc0, 0x82, 0x2b,
+	0xe0, 0x1c, 0x0f, 0x4c, 0x0b, 0x8b, 0x85, 0x4c, 0x09, 0xee, 0x92, 0x5b, 0x8c, 0x01, 0x7d, 0xee,
+	0xf7, 0xea, 0x62, 0x53, 0xd8, 0x58, 0x68, 0x37, 0x23, 0xd1, 0xb9, 0xed, 0x22, 0xf6, 0x34, 0x9d,
+	0x60, 0xcb, 0xef, 0xf7, 0xc7, 0xbe, 0xe0, 0x2e, 0xb1, 0xe0, 0x6e, 0x47, 0xd8, 0x63, 0xec, 0x9f,
+	0x18, 0xe8, 0x2b, 0x50, 0x32, 0xad, 0x3e, 0xb6, 0xba, 0xe7, 0x13, 0x1e, 0xc4, 0x45, 0xf6, 0xbe,
+	0x35, 0x41, 0xcf, 0x21, 0x7f, 0xad, 0xe1, 0x1b, 0x16, 0xa6, 0x0b, 0xed, 0x47, 0x49, 0x39, 0xe4,
+	0x9d, 0x86, 0x6f, 0x14, 0x86, 0xfc, 0xaf, 0xdc, 0x49, 0x26, 0xd0, 0x08, 0x2a, 0x6e, 0x8f, 0x4c,
+	0xc3, 0xc6, 0xe8, 0x05, 0x94, 0xf8, 0xca, 0xb6, 0x28, 0xb0, 0x1d, 0x8b, 0x49, 0xaa, 0x28, 0x1e,
+	0x12, 0xad, 0xc3, 0xa2, 0x81, 0x6f, 0x49, 0x37, 0xe2, 0x57, 0x35, 0x4a, 0x3e, 0x71, 0x7d, 0x4b,
+	0x56, 0x60, 0x61, 0x1f, 0x93, 0xad, 0xc9, 0x41, 0xdf, 0xf5, 0xd4, 0x70, 0x35, 0x70, 0xcd, 0x90,
+	0x9b, 0xd5, 0x0c, 0xf2, 0xef, 0xe0, 0x3e, 0x93, 0xb9, 0x4b, 0x1d, 0xd4, 0x15, 0xeb, 0x15, 0x10,
+	0xc1, 0x5f, 0x40, 0xee, 0x2e, 0xfc, 0x18, 0xa4, 0xce, 0x98, 0x5c, 0x62, 0x83, 0x68, 0x3d, 0x95,
+	0xe0, 0x99, 0x56, 0x91, 0xa0, 0xe4, 0x26, 0x59, 0x6e, 0x05, 0xef, 0x5d, 0x7e, 0x01, 0x8f, 0xf6,
+	0xb1, 0x81, 0x2d, 0x95, 0xe0, 0x13, 0x4e, 0x63, 0x96, 0x49, 0x95, 0x28, 0x8f, 0x60, 0x35, 0x81,
+	0x8b, 0x9f, 0x5a, 0x03, 0xe6, 0x1d, 0xab, 0x73, 0x36, 0xf6, 0x42, 0x53, 0x2f, 0xbe, 0x1d, 0x69,
+	0x16, 0xb6, 0x69, 0xf6, 0xcb, 0x65, 0xa7, 0x5e, 0x8e, 0xee, 0x10, 0xf9, 0x0b, 0x68, 0xb0, 0x02,
+	0x71, 0xe2, 0x55, 0x0b, 0x4f, 0xbf, 0x98, 0x85, 0xd2, 0x76, 0xfc, 0x0c, 0x96, 0x78, 0x80, 0xb9,
+	0x6e, 0x93, 0x26, 0x4a, 0xfe, 0x87, 0x00, 0x8d, 0x6d, 0x16, 0xc3, 0x21, 0x78, 0x1b, 0x8a, 0xfc,
+	0xb8, 0x18, 0x43, 0x9a, 0x5f, 0xba, 0xc0, 0x34, 0xbd, 0xd0, 0x4f, 0x61, 0x9e, 0xa5, 0x6b, 0x96,
+	0xdf, 0x2a, 0xed, 0x66, 0x5c, 0xf2, 0x3e, 0x25, 0xa6, 0x85, 0xb9, 0x02, 0x8a, 0x03, 0x97, 0xff,
+	0x9a, 0x83, 0xc6, 0x19, 0x2b, 0x51, 0x21, 0x05, 0xc3, 0x9e, 0xfc, 0x03, 0x2c, 0xee, 0x37, 0x42,
+	0x7e, 0x56, 0x23, 0xbc, 0x82, 0x8a, 0x53, 0x52, 0x59, 0x43, 0x96, 0x98, 0x85, 0xf7, 0x68, 0xcf,
+	0x76, 0xa4, 0xda, 0x57, 0x0a, 0xaf, 0xd7, 0xf4, 0xd9, 0xdf, 0x99, 0x14, 0x02, 0x9d, 0x89, 0xfc,
+	0x4b, 0x68, 0xec, 0x60, 0x1d, 0x67, 0x9a, 0xc1, 0x27, 0x21, 0x17, 0x94, 0xf0, 0xef, 0x39, 0x28,
+	0x9e, 0x62, 0x9b, 0x3e, 0x47, 0xb8, 0x56, 0x01, 0xdc, 0x8d, 0x69, 0xae, 0xf9, 0xca, 0x9c, 0x72,
+	0xd0, 0xa7, 0x35, 0x4a, 0xed, 0xf5, 0xb0, 0x6d, 0xf3, 0x64, 0xc3, 0x6b, 0x94, 0x43, 0x73, 0xca,
+	0xd8, 0x1a, 0xd4, 0x2c, 0x3c, 0xb0, 0xb0, 0x7d, 0xc9, 0x31, 0xbc, 0x46, 0x71, 0xa2, 0x03, 0xfa,
+	0x1a, 0x1e, 0xf8, 0xe5, 0x74, 0x7d, 0xe1, 0x92, 0x5d, 0xad, 0x1a, 0xbe, 0xe5, 0x76, 0xdd, 0xc8,
+	0x41, 0xa7, 0x20, 0x06, 0xd6, 0xf5, 0xcb, 0xcc, 0xae, 0x5f, 0x4b, 0x7e, 0xf5, 0xa6, 0x42, 0x57,
+	0x01, 0xc6, 0x36, 0xb6, 0xba, 0xea, 0x05, 0x36, 0x08, 0x2b, 0x64, 0x65, 0xa5, 0x4c, 0x29, 0x1d,
+	0x4a, 0x40, 0xcb, 0x50, 0xe8, 0x63, 0x7a, 0xfa, 0xac, 0x7d, 0x2c, 0x2b, 0xfc, 0x2d, 0xd4, 0xb6,
+	0x95, 0xef, 0xd2, 0xb6, 0xbd, 0x86, 0x2a, 0x6b, 0x9e, 0x6c, 0x8c, 0x59, 0xef, 0x04, 0x99, 0xcc,
+	0x40, 0xf1, 0xa7, 0x18, 0x1b, 0x1d, 0x22, 0xff, 0xdd, 0x8b, 0x62, 0x7e, 0xc0, 0xdf, 0x3b, 0x63,
+	0x86, 0xb6, 0x3e, 0x97, 0xbc, 0xf5, 0x7c, 0x60, 0xeb, 0x2b, 0x50, 0x62, 0xad, 0x92, 0xd9, 0xc7,
+	0xbc, 0xd1, 0x2f, 0x0e, 0x07, 0xea, 0xb6, 0xd9, 0xc7, 0xf2, 0x6b, 0x58, 0x52, 0x1c, 0x2b, 0x87,
+	0x94, 0x8b, 0xb8, 0x8c, 0x10, 0x75, 0x19, 0x79, 0x9d, 0x66, 0xc6, 0x6b, 0xf3, 0x2a, 0xbc, 0xb3,
+	0x90, 0x07, 0xcb, 0x2f, 0x9c, 0xce, 0x8c, 0xa3, 0xbc, 0xce, 0x2c, 0xe8, 0xd8, 0x42, 0xc8, 0xb1,
+	0xe5, 0x43, 0x68, 0x04, 0xb9, 0xa6, 0x65, 0xd9, 0xe6, 0xb4, 0xc4, 0xb2, 0xec, 0x2a, 0xe4, 0x21,
+	0xe5, 0x97, 0x20, 0x3a, 0xba, 0x76, 0x74, 0xfd, 0x8e, 0x8a, 0x7c, 0x03, 0xf7, 0x0f, 0x6c, 0x7b,
+	0x8c, 0xb3, 0xab, 0x53, 0xea, 0xe9, 0xf9, 0x8f, 0x61, 0x2e, 0x78, 0x0c, 0x18, 0x90, 0x7f, 0x85,
+	0x1f, 0xaa, 0x92, 0x2d, 0x43, 0x63, 0x1f, 0x93, 0x93, 0xf1, 0xb9, 0xae, 0xf5, 0xbe, 0xc4, 0x13,
+	0x77, 0xff, 0xf2, 0xdf, 0x04, 0x28, 0x7b, 0x54, 0xd6, 0x3a, 0x91, 0x69, 0xeb, 0x44, 0x1c, 0x8a,
+	0x97, 0x7a, 0xe8, 0x23, 0xa5, 0x8c, 0x6d, 0x77, 0x1b, 0xf4, 0x91, 0x52, 0x54, 0xfd, 0x82, 0x7b,
+	0x1e, 0x7d, 0x44, 0x55, 0x10, 0xdc, 0xc1, 0x52, 0x30, 0xe8, 0x1b, 0xe6, 0xa3, 0xa3, 0xc0, 0xd0,
+	0x3d, 0xeb, 0x9a, 0x47, 0x2f, 0x7d, 0xa4, 0xdf, 0x6f, 0x79, 0xc8, 0x0a, 0xb7, 0xf2, 0x3e, 0x2c,
+	0x85, 0x34, 0xe5, 0x36, 0x69, 0x41, 0xfe, 0x0a, 0x4f, 0xdc, 0x83, 0x97, 0x22, 0x07, 0xef, 0xb1,
+	0x28, 0x0c, 0x27, 0x77, 0xa1, 0x7a, 0x34, 0x50, 0xb7, 0xdd, 0x29, 0xe1, 0x7f, 0x6f, 0xd3, 0xe7,
+	0xb0, 0xb8, 0x6b, 0x58, 0xa6, 0x7e, 0x34, 0x50, 0x67, 0x74, 0xa7, 0xd7, 0x50, 0x9f, 0x72, 0xf0,
+	0x6d, 0x2d, 0x43, 0xc1, 0xc6, 0x3d, 0x0b, 0x13, 0x0e, 0xe7, 0x6f, 0xcc, 0xce, 0x96, 0xe6, 0x5a,
+	0x7e, 0x6c, 0x69, 0xf2, 0x3e, 0xa0, 0x4e, 0x8f, 0x68, 0xd7, 0xb4, 0x2a, 0xcd, 0xba, 0x24, 0xbd,
+	0x57, 0x60, 0x6e, 0xc7, 0xef, 0x15, 0xe8, 0xb3, 0xdc, 0x86, 0xfb, 0x3b, 0x9a, 0xad, 0x9e, 0xeb,
+	0xb3, 0xcb, 0x91, 0x3f, 0x9b, 0xb6, 0x6c, 0x0a, 0xee, 0x99, 0xd7, 0xd8, 0x9a, 0x50, 0xff, 0x9d,
+	0x35, 0x90, 0x3e, 0x85, 0xd5, 0x04, 0xf6, 0xa9, 0xc7, 0x53, 0xdd, 0x9c, 0xe3, 0x2d, 0x2b, 0xce,
+	0x8b, 0xbc, 0x03, 0xf5, 0x77, 0xd8, 0xd2, 0x06, 0x13, 0x9f, 0xa2, 0x74, 0x46, 0x77, 0x0f, 0xd5,
+	0x5d, 0xc8, 0x23, 0xc4, 0xee, 0xf7, 0x53, 0x68, 0x9c, 0x19, 0xba, 0xd9, 0xbb, 0x0a, 0x15, 0xe9,
+	0x0c, 0x9d, 0x37, 0xe1, 0x01, 0x47, 0xb2, 0x59, 0xf8, 0x50, 0x33, 0xae, 0xd2, 0x1b, 0xd4, 0x3f,
+	0x09, 0x20, 0x72, 0x84, 0x8f, 0x63, 0xba, 0xc1, 0x28, 0xcb, 0xd4, 0x29, 0x73, 0xc9, 0x4e, 0x39,
+	0x77, 0x17, 0xa7, 0x1c, 0xc0, 0x83, 0x6d, 0xd3, 0xb0, 0xc7, 0x43, 0x1c, 0xa7, 0x74, 0x4c, 0x00,
+	0x04, 0x2b, 0x4b, 0x2e, 0xb9, 0xb2, 0xcc, 0xf9, 0x2b, 0x8b, 0xfc, 0x67, 0x01, 0xc4, 0xe8, 0x42,
+	0x7c, 0xaf, 0xdf, 0xa7, 0x4b, 0x6d, 0x43, 0x91, 0x67, 0x6c, 0x31, 0x97, 0xc0, 0xe3, 0xa6, 0x76,
+	0x17, 0x28, 0x77, 0x60, 0x85, 0x6f, 0x8e, 0x0d, 0x24, 0xdb, 0x97, 0xaa, 0x71, 0x81, 0x93, 0x5a,
+	0x30, 0xef, 0x00, 0x72, 0xfe, 0x33, 0xfb, 0x4e, 0x80, 0x8a, 0x8f, 0x39, 0x2b, 0x9c, 0x1e, 0x42,
+	0xd9, 0xd4, 0xfb, 0x5d, 0xbf, 0xa0, 0x92, 0xa9, 0xf7, 0x99, 0x04, 0xfa, 0xd1, 0xc0, 0x37, 0x5d,
+	0xff, 0x9d, 0x5d, 0xc9, 0xc0, 0x37, 0xbb, 0xc1, 0x93, 0xce, 0x27, 0x9f, 0xf4, 0xfc, 0x5d, 0x4e,
+	0xfa, 0x63, 0x58, 0xe1, 0x23, 0x45, 0xcc, 0xe6, 0xe3, 0xc7, 0x8a, 0x8f, 0xa9, 0xbd, 0x6c, 0x6c,
+	0xf4, 0x39, 0xa3, 0x4a, 0xb2, 0x9a, 0x12, 0xf9, 0x2f, 0x02, 0x48, 0x71, 0x3c, 0xff, 0x5f, 0xaf,
+	0x7e, 0x05, 0xcb, 0xef, 0x54, 0x5d, 0xeb, 0x47, 0x5b, 0xa9, 0x70, 0x0f, 0x2c, 0x44, 0x7a, 0xe0,
+	0xa7, 0x43, 0x58, 0x0c, 0x5d, 0x6e, 0x20, 0x11, 0x1a, 0xdb, 0x5f, 0x1d, 0xef, 0x1d, 0x28, 0x47,
+	0xbb, 0x3b, 0xdd, 0xbd, 0x83, 0xc3, 0xb7, 0xbb, 0x4a, 0xb7, 0x73, 0xfc, 0x9b, 0xfa, 0x3d, 0xf4,
+	0x23, 0x90, 0x22, 0x5f, 0x3c, 0x42, 0x5d, 0x40, 0x4d, 0x78, 0x14, 0xf9, 0x7e, 0x76, 0x3c, 0x45,
+	0xe4, 0x9e, 0x3e, 0x83, 0x8a, 0x6f, 0x82, 0x46, 0x25, 0xc8, 0xef, 0x9d, 0x1d, 0x1e, 0xd6, 0xef,
+	0xa1, 0x32, 0xcc, 0x6f, 0x75, 0x4e, 0x0f, 0xb6, 0xeb, 0x02, 0x7d, 0xec, 0xec, 0x1c, 0x1d, 0x1c,
+	0xd7, 0x73, 0xed, 0x7f, 0x36, 0x60, 0x81, 0xe3, 0x4f, 0x1d, 0x3f, 0x47, 0x67, 0x90, 0xa7, 0xed,
+	0x0f, 0xfa, 0x60, 0x96, 0x5b, 0x16, 0xe9, 0xc3, 0x0c, 0x94, 0x73, 0x52, 0xf2, 0x3d, 0xb4, 0x07,
+	0x45, 0x7e, 0xed, 0x80, 0xde, 0x8f, 0xf0, 0x04, 0x2f, 0x24, 0xa4, 0xc4, 0x80, 0x95, 0xef, 0xa1,
+	0x43, 0x80, 0xe9, 0x55, 0x03, 0x92, 0xe3, 0x45, 0xf9, 0x6f, 0x08, 0x52, 0xa5, 0xfd, 0x01, 0xde,
+	0x8b, 0xb9, 0x5b, 0x40, 0x1f, 0x45, 0x59, 0x12, 0x6f, 0x20, 0x52, 0xe5, 0xdf, 0xc2, 0x92, 0x5b,
+	0x79, 0x02, 0xb7, 0x06, 0xe8, 0x59, 0x8c, 0xe2, 0xc9, 0x77, 0x12, 0x52, 0x6b, 0x56, 0xb8, 0x67,
+	0x6f, 0x05, 0x6a, 0x81, 0xdb, 0x03, 0x14, 0x3d, 0xa9, 0xb8, 0xdb, 0x85, 0xd4, 0xdd, 0xbc, 0x85,
+	0x85, 0xe0, 0x3d, 0x02, 0x5a, 0x4f, 0xba, 0xc9, 0x0b, 0x16, 0xbb, 0x54, 0xa9, 0x5f, 0x42, 0xc1,
+	0x99, 0x53, 0x62, 0x54, 0x8c, 0xbb, 0x86, 0xc8, 0x12, 0xe6, 0xdc, 0x0c, 0xc4, 0x08, 0x8b, 0xbb,
+	0x32, 0x48, 0x15, 0x76, 0x00, 0x05, 0x67, 0xbe, 0x8e, 0x11, 0x16, 0x37, 0x78, 0x4b, 0xcb, 0x91,
+	0x84, 0xb2, 0x4b, 0x7f, 0xf9, 0x71, 0x8e, 0x23, 0x30, 0x8c, 0x25, 0xee, 0x35, 0x98, 0x61, 0xa4,
+	0xc4, 0x3a, 0xe4, 0x1c, 0x47, 0x70, 0x88, 0x8a, 0x39, 0x8e, 0xd8, 0x29, 0x2b, 0x55, 0xea, 0x09,
+	0xd4, 0x9c, 0x81, 0x25, 0x59, 0xd3, 0xb8, 0xe1, 0x2b, 0x65, 0xef, 0xbf, 0x87, 0xaa, 0x7f, 0xa0,
+	0x4a, 0xc8, 0x2c, 0xa1, 0xe1, 0x48, 0xfa, 0x30, 0x03, 0xe5, 0x79, 0xfa, 0xaf, 0xe1, 0x7e, 0x64,
+	0xc2, 0x42, 0x4f, 0x12, 0x94, 0x8e, 0x4e, 0x61, 0x29, 0x8a, 0xff, 0x0a, 0x60, 0x3a, 0x1e, 0xc5,
+	0xe4, 0x9a, 0xc8, 0x74, 0x26, 0xad, 0xa5, 0x62, 0x3c, 0x95, 0xbf, 0x81, 0x5a, 0x60, 0xcc, 0x88,
+	0xb1, 0x71, 0xdc, 0xc0, 0x24, 0xad, 0x67, 0xc1, 0xbc, 0x15, 0xbe, 0x86, 0x92, 0xdb, 0xec, 0xa3,
+	0xe8, 0x75, 0x7b, 0x68, 0x72, 0x90, 0x1e, 0xa7, 0x20, 0x3c, 0x91, 0x87, 0x50, 0xf1, 0x4d, 0x00,
+	0x68, 0x2d, 0x26, 0x70, 0xc2, 0xf3, 0x41, 0x8a, 0x6d, 0xdf, 0x00, 0x4c, 0xc7, 0x80, 0x18, 0xdb,
+	0x46, 0x66, 0x84, 0x14, 0x59, 0xbe, 0x2c, 0x1b, 0xe8, 0xef, 0x53, 0xb2, 0x6c, 0xdc, 0x18, 0x21,
+	0xb5, 0x66, 0x85, 0x7b, 0x36, 0x79, 0x03, 0x65, 0x6f, 0x44, 0x40, 0x51, 0x2b, 0x86, 0xc7, 0x87,
+	0xd4, 0x6c, 0x73, 0x02, 0xb5, 0xc0, 0xa0, 0x10, 0x97, 0xc1, 0x62, 0x06, 0x89, 0x14, 0xbb, 0x68,
+	0x50, 0x0f, 0x4f, 0x04, 0x68, 0x23, 0x26, 0x30, 0x62, 0xc7, 0x0c, 0xe9, 0xc9, 0x0c, 0x48, 0xcf,
+	0x10, 0x1a, 0xd4, 0xc3, 0x0d, 0x79, 0xcc, 0x52, 0x09, 0xc3, 0x81, 0xf4, 0x64, 0x06, 0xa4, 0x2f,
+	0x78, 0x50, 0xb4, 0xef, 0x46, 0x4f, 0x93, 0xb4, 0x8d, 0xf6, 0xa7, 0x52, 0xf4, 0x57, 0x07, 0x1f,
+	0xc8, 0x59, 0x21, 0xda, 0xdc, 0xc6, 0xac, 0x90, 0xd8, 0x01, 0x67, 0xae, 0x60, 0x02, 0x8a, 0xf6,
+	0xb5, 0xb1, 0x7b, 0x48, 0x68, 0x98, 0xa5, 0x8f, 0x66, 0xc2, 0x7a, 0x46, 0x7b, 0x07, 0x8b, 0xa1,
+	0x1e, 0x16, 0xfd, 0x38, 0xea, 0xae, 0xb1, 0x5d, 0x6e, 0x5a, 0xb5, 0xd8, 0x5a, 0xfb, 0xed, 0xe3,
+	0xe8, 0xff, 0x19, 0x42, 0xf0, 0xf3, 0x02, 0xf3, 0xcc, 0x4f, 0xfe, 0x33, 0x00, 0x4b, 0xe4, 0xab,
+	0xb1, 0x40, 0x21, 0x00, 0x00,
 }
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/lileio/account_service/blocklist"
+	"github.com/spf13/cobra"
+)
+
+var blocklistOut string
+var blocklistHashed bool
+
+var blocklistCmd = &cobra.Command{
+	Use:   "blocklist",
+	Short: "Manage the breached password blocklist",
+}
+
+var buildBlocklistCmd = &cobra.Command{
+	Use:   "build [list]",
+	Short: "Build a blocklist index from a list of passwords, or stdin",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if blocklistOut == "" {
+			log.Fatal("an output path is required, set --out")
+		}
+
+		var in io.Reader = os.Stdin
+		if len(args) == 1 && args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+
+			in = f
+		}
+
+		// build next to the index and rename so servers never open half of one
+		tmp := blocklistOut + ".tmp"
+		out, err := os.Create(tmp)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		n, err := blocklist.Build(out, in, blocklistHashed)
+		if err == nil {
+			err = out.Close()
+		}
+
+		if err == nil {
+			err = os.Rename(tmp, blocklistOut)
+		}
+
+		if err != nil {
+			os.Remove(tmp)
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%d passwords\n", n)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(blocklistCmd)
+	blocklistCmd.AddCommand(buildBlocklistCmd)
+
+	buildBlocklistCmd.Flags().StringVarP(&blocklistOut, "out", "o", os.Getenv("PASSWORD_BLOCKLIST"), "path of the index, defaults to PASSWORD_BLOCKLIST")
+	buildBlocklistCmd.Flags().BoolVarP(&blocklistHashed, "sha1", "", false, "the list is of sorted SHA-1 hashes, as downloaded from Have I Been Pwned")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lileio/account_service/jwt"
+	"github.com/spf13/cobra"
+)
+
+var keysDir string
+var keyAlg string
+var keepKeys int
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the keys used to sign JWTs",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if keysDir == "" {
+			log.Fatal("a key directory is required, set --dir or JWT_KEYS_DIR")
+		}
+	},
+}
+
+var generateKeyCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a new signing key, keeping existing keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := jwt.GenerateKey(keysDir, keyAlg)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(k.ID)
+	},
+}
+
+var rotateKeysCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Generate a new signing key and remove all but the newest --keep keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := jwt.Rotate(keysDir, keyAlg, keepKeys)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(k.ID)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(generateKeyCmd)
+	keysCmd.AddCommand(rotateKeysCmd)
+
+	keysCmd.PersistentFlags().StringVarP(&keysDir, "dir", "d", os.Getenv("JWT_KEYS_DIR"), "directory keys are kept in, defaults to JWT_KEYS_DIR")
+	keysCmd.PersistentFlags().StringVarP(&keyAlg, "alg", "", jwt.RS256, "algorithm of the new key, RS256 or EdDSA")
+	rotateKeysCmd.Flags().IntVarP(&keepKeys, "keep", "k", 2, "number of keys to keep, including the new one")
+}
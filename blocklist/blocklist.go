@@ -0,0 +1,206 @@
+// Package blocklist checks passwords against a list of known breached or
+// common passwords without any network calls. Lists are built into an index
+// of sorted SHA-1 digests which is binary searched on disk, so even lists
+// of hundreds of millions of passwords use next to no memory.
+package blocklist
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// magic starts every index, followed by the digests
+const magic = "pwblock1"
+
+var (
+	ErrInvalidIndex = errors.New("not a blocklist index")
+	ErrUnsorted     = errors.New("hashes are not sorted")
+)
+
+// Blocklist is an index opened for checking, it's safe for concurrent use
+type Blocklist struct {
+	f *os.File
+	n int64
+}
+
+// Open opens the index at path, as written by Build
+func Open(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header := make([]byte, len(magic))
+	_, err = f.ReadAt(header, 0)
+	size := fi.Size() - int64(len(magic))
+	if err != nil || string(header) != magic || size%sha1.Size != 0 {
+		f.Close()
+		return nil, ErrInvalidIndex
+	}
+
+	return &Blocklist{f: f, n: size / sha1.Size}, nil
+}
+
+// Len returns the number of passwords in the index
+func (b *Blocklist) Len() int64 {
+	return b.n
+}
+
+// Close closes the index file
+func (b *Blocklist) Close() error {
+	return b.f.Close()
+}
+
+// Contains reports whether password is in the index
+func (b *Blocklist) Contains(password string) (bool, error) {
+	d := sha1.Sum([]byte(password))
+	return b.contains(d[:])
+}
+
+func (b *Blocklist) contains(digest []byte) (bool, error) {
+	buf := make([]byte, sha1.Size)
+	var err error
+
+	i := sort.Search(int(b.n), func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		_, err = b.f.ReadAt(buf, int64(len(magic))+int64(i)*sha1.Size)
+		return bytes.Compare(buf, digest) >= 0
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if int64(i) == b.n {
+		return false, nil
+	}
+
+	_, err = b.f.ReadAt(buf, int64(len(magic))+int64(i)*sha1.Size)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(buf, digest), nil
+}
+
+// Build writes an index of the list read from r to w, returning how many
+// passwords it holds. The list has one entry a line, either plain passwords
+// or, when hashed is true, hex SHA-1 digests as published by Have I Been
+// Pwned, optionally followed by :count. Plain passwords are sorted in
+// memory, digests have to be sorted already and are streamed so lists of
+// any size can be indexed.
+func Build(w io.Writer, r io.Reader, hashed bool) (int64, error) {
+	bw := bufio.NewWriter(w)
+	_, err := bw.WriteString(magic)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if hashed {
+		n, err = writeHashes(bw, r)
+	} else {
+		n, err = writePasswords(bw, r)
+	}
+
+	if err != nil {
+		return n, err
+	}
+
+	return n, bw.Flush()
+}
+
+func writeHashes(w io.Writer, r io.Reader) (int64, error) {
+	var n int64
+	var last []byte
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		h := strings.TrimSpace(s.Text())
+		if i := strings.IndexByte(h, ':'); i >= 0 {
+			h = h[:i]
+		}
+
+		if h == "" {
+			continue
+		}
+
+		d, err := hex.DecodeString(h)
+		if err != nil || len(d) != sha1.Size {
+			return n, fmt.Errorf("line %d: not a SHA-1 hash", line)
+		}
+
+		c := bytes.Compare(d, last)
+		if last != nil && c < 0 {
+			return n, fmt.Errorf("line %d: %v", line, ErrUnsorted)
+		}
+
+		if last != nil && c == 0 {
+			continue
+		}
+
+		_, err = w.Write(d)
+		if err != nil {
+			return n, err
+		}
+
+		last = d
+		n++
+	}
+
+	return n, s.Err()
+}
+
+func writePasswords(w io.Writer, r io.Reader) (int64, error) {
+	digests := [][sha1.Size]byte{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		p := strings.TrimRight(s.Text(), "\r")
+		if p == "" {
+			continue
+		}
+
+		digests = append(digests, sha1.Sum([]byte(p)))
+	}
+
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+
+	sort.Slice(digests, func(i, j int) bool {
+		return bytes.Compare(digests[i][:], digests[j][:]) < 0
+	})
+
+	var n int64
+	for i, d := range digests {
+		if i > 0 && d == digests[i-1] {
+			continue
+		}
+
+		_, err := w.Write(d[:])
+		if err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,115 @@
+package blocklist
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildIndex(t *testing.T, list string, hashed bool) (*Blocklist, func()) {
+	dir, err := ioutil.TempDir("", "blocklist")
+	assert.Nil(t, err)
+
+	path := filepath.Join(dir, "index")
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+
+	_, err = Build(f, strings.NewReader(list), hashed)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	b, err := Open(path)
+	assert.Nil(t, err)
+
+	return b, func() {
+		b.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func sha1Hex(p string) string {
+	d := sha1.Sum([]byte(p))
+	return strings.ToUpper(hex.EncodeToString(d[:]))
+}
+
+func TestPasswords(t *testing.T) {
+	b, cleanup := buildIndex(t, "password\n123456\nqwerty\n\npassword\nletmein\r\n", false)
+	defer cleanup()
+
+	assert.Equal(t, int64(4), b.Len())
+
+	for _, p := range []string{"password", "123456", "qwerty", "letmein"} {
+		ok, err := b.Contains(p)
+		assert.Nil(t, err)
+		assert.True(t, ok, p)
+	}
+
+	for _, p := range []string{"", "Password", "correct horse battery staple", "zzzzzzzz"} {
+		ok, err := b.Contains(p)
+		assert.Nil(t, err)
+		assert.False(t, ok, p)
+	}
+}
+
+func TestHashes(t *testing.T) {
+	// HIBP lists are sorted by hash with a count after each one
+	sorted := []string{sha1Hex("password"), sha1Hex("123456"), sha1Hex("qwerty")}
+	sort.Strings(sorted)
+
+	list := sorted[0] + ":3861493\n" + sorted[1] + ":20\n" + sorted[1] + "\n" + strings.ToLower(sorted[2]) + "\n"
+	b, cleanup := buildIndex(t, list, true)
+	defer cleanup()
+
+	assert.Equal(t, int64(3), b.Len())
+
+	ok, err := b.Contains("qwerty")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = b.Contains("letmein")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestHashesUnsorted(t *testing.T) {
+	list := strings.Repeat("F", 40) + "\n" + strings.Repeat("0", 40) + "\n"
+	_, err := Build(&bytes.Buffer{}, strings.NewReader(list), true)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), ErrUnsorted.Error())
+
+	_, err = Build(&bytes.Buffer{}, strings.NewReader("password\n"), true)
+	assert.NotNil(t, err)
+}
+
+func TestEmpty(t *testing.T) {
+	b, cleanup := buildIndex(t, "", false)
+	defer cleanup()
+
+	assert.Equal(t, int64(0), b.Len())
+	ok, err := b.Contains("password")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestOpenInvalid(t *testing.T) {
+	f, err := ioutil.TempFile("", "blocklist")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("password\n123456\n")
+	f.Close()
+
+	_, err = Open(f.Name())
+	assert.Equal(t, ErrInvalidIndex, err)
+
+	_, err = Open(f.Name() + ".missing")
+	assert.NotNil(t, err)
+}
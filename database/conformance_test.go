@@ -0,0 +1,70 @@
+package database_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/database/dbtest"
+)
+
+func TestMemoryConformance(t *testing.T) {
+	db := &database.Memory{}
+	dbtest.RunConformance(t, func(t *testing.T) database.Database {
+		return db
+	})
+}
+
+func TestPostgreSQLConformance(t *testing.T) {
+	conn := os.Getenv("POSTGRESQL_URL")
+	if conn == "" {
+		t.Skip("POSTGRESQL_URL not set")
+	}
+
+	db := &database.PostgreSQL{}
+	err := db.Connect(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dbtest.RunConformance(t, func(t *testing.T) database.Database {
+		return db
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		t.Skip("SQLITE_PATH not set")
+	}
+
+	db := &database.SQLite{}
+	err := db.Connect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dbtest.RunConformance(t, func(t *testing.T) database.Database {
+		return db
+	})
+}
+
+func TestMySQLConformance(t *testing.T) {
+	conn := os.Getenv("MYSQL_URL")
+	if conn == "" {
+		t.Skip("MYSQL_URL not set")
+	}
+
+	db := &database.MySQL{}
+	err := db.Connect(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dbtest.RunConformance(t, func(t *testing.T) database.Database {
+		return db
+	})
+}
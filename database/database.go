@@ -3,72 +3,382 @@ package database
 import (
 	"errors"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/lileio/image_service"
 
-	"golang.org/x/crypto/bcrypt"
-
 	validator "gopkg.in/go-playground/validator.v9"
 )
 
 var (
 	validate = validator.New()
 
-	ErrAccountNotFound = errors.New("account not found")
-	ErrEmailExists     = errors.New("email already exists")
-	ErrNoDatabase      = errors.New("no database connection details")
-	ErrNoPasswordGiven = errors.New("a password is required")
+	ErrAccountNotFound  = errors.New("account not found")
+	ErrEmailExists      = errors.New("email already exists")
+	ErrNoDatabase       = errors.New("no database connection details")
+	ErrNoPasswordGiven  = errors.New("a password is required")
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidOrderBy   = errors.New("invalid order by")
+	ErrUnknownField     = errors.New("unknown field")
+	ErrVersionConflict  = errors.New("account version conflict")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrMfaNotFound      = errors.New("mfa not enrolled")
+	ErrMfaEnabled       = errors.New("mfa already enabled")
+	ErrMfaCodeUsed      = errors.New("mfa code already used")
+	ErrNoMfaChallenge   = errors.New("mfa challenge not found")
+	ErrPasswordMismatch = errors.New("password incorrect")
+	ErrUnknownHash      = errors.New("unknown password hash")
+	ErrUnknownPepper    = errors.New("unknown password pepper")
+	ErrNoLoginLink      = errors.New("login link not found")
+	ErrConfirmed        = errors.New("account already confirmed")
+
+	// PasswordResetTTL is how long a password reset token can be used for
+	// once generated, it can be changed with PASSWORD_RESET_TTL
+	PasswordResetTTL = 24 * time.Hour
+
+	// ConfirmationTTL is how long a confirmation token can be used for once
+	// generated, it can be changed with CONFIRMATION_TTL
+	ConfirmationTTL = 7 * 24 * time.Hour
+
+	// EmailChangeTTL is how long the token confirming an email change can
+	// be used for, it can be changed with EMAIL_CHANGE_TTL
+	EmailChangeTTL = 24 * time.Hour
+
+	// UpdateFields are the fields Update can change, all of them are
+	// updated when none are given. hashed_password can also be given to
+	// change the password. The server always passes the fields it means to
+	// change, never email, as emails only change through
+	// ConfirmEmailChange.
+	UpdateFields = []string{"name", "email", "images", "metadata"}
+
+	// validatedFields maps fields to the struct fields they're validated as
+	validatedFields = map[string]string{
+		"name":  "Name",
+		"email": "Email",
+	}
 )
 
 type Database interface {
-	List(count int32, token string) ([]*Account, string, error)
+	List(count int32, token string, opts ListOptions) ([]*Account, string, error)
 	ReadByID(ID string) (*Account, error)
+	// ReadByEmail, GeneratePasswordToken and GenerateConfirmationToken
+	// match emails ignoring case, as they're unique ignoring case
 	ReadByEmail(email string) (*Account, error)
 	Create(a *Account, password string) error
-	Update(a *Account) error
-	Delete(ID string) error
+	// Update and Delete fail with ErrVersionConflict when the stored
+	// account doesn't have the expected version, a version of 0 skips the
+	// check. Every write increments the version and sets UpdatedAt.
+	Update(a *Account, fields ...string) error
+	Delete(ID string, version int64) error
+	// Confirm confirms the account with the given confirmation token and
+	// sets ConfirmedAt, ErrTokenExpired is returned if the token has expired.
+	// GenerateConfirmationToken replaces the token of an unconfirmed account,
+	// failing with ErrConfirmed once it's been confirmed.
 	Confirm(token string) (*Account, error)
+	GenerateConfirmationToken(email string) (*Account, error)
+	// RecordLogin sets LastLoginAt on a and stores it, logging in isn't a
+	// write to the account so neither the version nor UpdatedAt change.
+	RecordLogin(a *Account) error
 	GeneratePasswordToken(email string) (*Account, error)
-	UpdatePassword(string, string) (*Account, error)
+	// ReadByPasswordToken returns the account with the given reset token,
+	// whether or not it has expired.
+	ReadByPasswordToken(token string) (*Account, error)
+	// UpdatePassword sets the password of the account with the given reset
+	// token and clears the token, ErrTokenExpired is returned if the token
+	// has expired.
+	UpdatePassword(token, hashedPassword, pepperID string) (*Account, error)
+	// RequestEmailChange stores email as the pending email of an account
+	// along with a token to confirm it, replacing any pending change. It
+	// fails with ErrEmailExists when another account has the email.
+	// ConfirmEmailChange swaps in the pending email of the account with the
+	// given token, checking again that it's free, and returns the account
+	// with PreviousEmail set. ErrTokenExpired is returned once the token has
+	// expired. Resetting the password cancels a pending change.
+	RequestEmailChange(ID, email string) (*Account, error)
+	ConfirmEmailChange(token string) (*Account, error)
+	// CreateSession stores a new session for s.AccountID and issues its
+	// tokens. RefreshSession replaces the tokens of the session with the
+	// given refresh token, ErrTokenExpired is returned once it has expired.
+	// TouchSession returns the session with the given access token and sets
+	// its LastSeenAt to now, ErrTokenExpired is returned once the access
+	// token has expired. Deleting an account revokes all of its sessions.
+	CreateSession(s *Session) error
+	RefreshSession(refreshToken string) (*Session, error)
+	TouchSession(accessToken string) (*Session, error)
+	ListSessions(accountID string) ([]*Session, error)
+	RevokeSession(ID string) error
+	RevokeAllSessions(accountID string) error
+	// ReadMfa returns the MFA enrolment of an account. EnrolMfa replaces an
+	// enrolment that hasn't been enabled yet, failing with ErrMfaEnabled
+	// once it has. Deleting an account deletes its enrolment.
+	ReadMfa(accountID string) (*Mfa, error)
+	EnrolMfa(accountID, secret string) error
+	EnableMfa(accountID string, step int64) error
+	DisableMfa(accountID string) error
+	SetRecoveryCodes(accountID string, hashes []string) error
+	// UseMfaStep and UseRecoveryCode fail with ErrMfaCodeUsed when the code
+	// has already been used, or for a recovery code that never existed.
+	UseMfaStep(accountID string, step int64) error
+	UseRecoveryCode(accountID, hash string) error
+	// CreateMfaChallenge issues a login challenge for an enrolment replacing
+	// any previous one. ConsumeMfaChallenge clears the challenge and returns
+	// the enrolment, ErrTokenExpired is returned once it has expired.
+	CreateMfaChallenge(accountID string) (*Mfa, error)
+	ConsumeMfaChallenge(token string) (*Mfa, error)
+	// ReadLoginFailures returns the failures counted for a subject, a zero
+	// count if there are none. RecordLoginFailure counts another one and
+	// ResetLoginFailures clears the count of each subject.
+	ReadLoginFailures(subject string) (*LoginFailures, error)
+	RecordLoginFailure(subject string) (*LoginFailures, error)
+	ResetLoginFailures(subjects ...string) error
+	// CreateLoginLink issues a login link for an email, accountID is empty
+	// when no account has it. A *RateLimitError is returned once
+	// LoginLinkLimit links have been requested for the email within
+	// LoginLinkWindow. ConsumeLoginLink marks a link used and returns it,
+	// ErrTokenExpired is returned once it has expired.
+	CreateLoginLink(email, accountID string) (*LoginLink, error)
+	ConsumeLoginLink(token string) (*LoginLink, error)
 	Migrate() error
 	Truncate() error
 	Close() error
 }
 
 type Account struct {
-	ID                 string `db:"id"`
-	Name               string `validate:"required"`
-	Email              string `validate:"required"`
-	HashedPassword     string `db:"hashed_password"`
-	ConfirmationToken  string
-	PasswordResetToken string
-	Images             []*image_service.Image
-	Metadata           map[string]string
-	CreatedAt          time.Time `db:"created_at"`
+	ID             string `db:"id"`
+	Name           string `validate:"required"`
+	Email          string `validate:"required"`
+	HashedPassword string `db:"hashed_password"`
+	// PepperID names the pepper the password was peppered with before
+	// hashing, empty if it wasn't
+	PepperID string `sql:"pepper_id"`
+	// ConfirmationToken, PasswordResetToken and EmailChangeToken are only
+	// set on the account returned when they're generated, just their
+	// digests are stored
+	ConfirmationToken      string    `sql:"-"`
+	PasswordResetToken     string    `sql:"-"`
+	EmailChangeToken       string    `sql:"-"`
+	ConfirmationTokenHash  string    `sql:"confirmation_token"`
+	ConfirmationExpiresAt  time.Time `db:"confirmation_expires_at"`
+	PasswordResetTokenHash string    `sql:"password_reset_token"`
+	PasswordResetExpiresAt time.Time `db:"password_reset_expires_at"`
+	Images                 []*image_service.Image
+	Metadata               map[string]string
+	CreatedAt              time.Time `db:"created_at"`
+	UpdatedAt              time.Time `db:"updated_at"`
+	ConfirmedAt            time.Time `db:"confirmed_at"`
+	LastLoginAt            time.Time `db:"last_login_at"`
+	Version                int64
+	// PendingEmail is the email an account is changing to once the change
+	// is confirmed, PreviousEmail is only set on the account returned by
+	// ConfirmEmailChange
+	PendingEmail         string    `sql:"pending_email"`
+	EmailChangeTokenHash string    `sql:"email_change_token"`
+	EmailChangeExpiresAt time.Time `db:"email_change_expires_at"`
+	PreviousEmail        string    `sql:"-"`
 }
 
 func (a *Account) Valid() error {
 	return validate.Struct(a)
 }
 
+// ValidFields validates only the given fields, as used by partial updates
+func (a *Account) ValidFields(fields []string) error {
+	names := []string{}
+	for _, f := range fields {
+		if n, ok := validatedFields[f]; ok {
+			names = append(names, n)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return validate.StructPartial(a, names...)
+}
+
+// updateFields checks the fields passed to Update, defaulting to all of
+// UpdateFields when none are given.
+func updateFields(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return UpdateFields, nil
+	}
+
+	for _, f := range fields {
+		known := f == "hashed_password"
+		for _, u := range UpdateFields {
+			known = known || f == u
+		}
+
+		if !known {
+			return nil, ErrUnknownField
+		}
+	}
+
+	return fields, nil
+}
+
+// accountReader reads accounts, it's what the helpers shared between drivers
+// need of a Database
+type accountReader interface {
+	ReadByID(ID string) (*Account, error)
+	ReadByEmail(email string) (*Account, error)
+}
+
+// versionError is returned when a write matched no rows, it tells a missing
+// account apart from one that has been changed since it was read.
+func versionError(db accountReader, ID string) error {
+	_, err := db.ReadByID(ID)
+	if err != nil {
+		return err
+	}
+
+	return ErrVersionConflict
+}
+
+// Confirmed reports whether the account has been confirmed
+func (a *Account) Confirmed() bool {
+	return a.ConfirmationTokenHash == ""
+}
+
+// hashTokens sets the digests of any tokens on a before it is stored, a new
+// confirmation token expires after ConfirmationTTL
+func (a *Account) hashTokens() {
+	if a.ConfirmationToken != "" {
+		a.ConfirmationTokenHash = HashToken(a.ConfirmationToken)
+	}
+
+	if a.ConfirmationToken != "" && a.ConfirmationExpiresAt.IsZero() {
+		a.ConfirmationExpiresAt = confirmationExpiry()
+	}
+
+	if a.PasswordResetToken != "" {
+		a.PasswordResetTokenHash = HashToken(a.PasswordResetToken)
+	}
+}
+
+// confirmationExpired reports whether the confirmation token can no longer
+// be used, tokens without an expiry are treated as expired.
+func (a *Account) confirmationExpired() bool {
+	return !a.ConfirmationExpiresAt.After(time.Now())
+}
+
+// confirmationExpiry returns the expiry for a confirmation token generated
+// now
+func confirmationExpiry() time.Time {
+	return time.Now().UTC().Add(ConfirmationTTL).Truncate(time.Microsecond)
+}
+
+// passwordResetExpired reports whether the reset token can no longer be
+// used, tokens without an expiry are treated as expired.
+func (a *Account) passwordResetExpired() bool {
+	return !a.PasswordResetExpiresAt.After(time.Now())
+}
+
+// passwordResetExpiry returns the expiry for a reset token generated now
+func passwordResetExpiry() time.Time {
+	return time.Now().UTC().Add(PasswordResetTTL).Truncate(time.Microsecond)
+}
+
+// emailChangeExpired reports whether the email change token can no longer
+// be used
+func (a *Account) emailChangeExpired() bool {
+	return !a.EmailChangeExpiresAt.After(time.Now())
+}
+
+// emailChangeExpiry returns the expiry for an email change token generated
+// now
+func emailChangeExpiry() time.Time {
+	return time.Now().UTC().Add(EmailChangeTTL).Truncate(time.Microsecond)
+}
+
+// cancelEmailChange clears any pending email change
+func (a *Account) cancelEmailChange() {
+	a.PendingEmail = ""
+	a.EmailChangeTokenHash = ""
+	a.EmailChangeExpiresAt = time.Time{}
+}
+
+// HashPassword sets the hashed password of a, peppered with the current
+// pepper and hashed by PasswordHasher
 func (a *Account) HashPassword(password string) error {
 	if password == "" {
 		return ErrNoPasswordGiven
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	id := currentPepperID()
+	peppered, err := pepper(password, id)
 	if err != nil {
 		return err
 	}
 
-	a.HashedPassword = string(hash[:])
+	hash, err := PasswordHasher.Hash(peppered)
+	if err != nil {
+		return err
+	}
+
+	a.HashedPassword = hash
+	a.PepperID = id
 
 	return nil
 }
 
+// ComparePasswordToHash returns ErrPasswordMismatch unless password is the
+// password of a, whichever algorithm and pepper it was hashed with
 func (a *Account) ComparePasswordToHash(password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(a.HashedPassword), []byte(password))
+	h, err := hasherFor(a.HashedPassword)
+	if err != nil {
+		return err
+	}
+
+	peppered, err := pepper(password, a.PepperID)
+	if err != nil {
+		return err
+	}
+
+	return h.Compare(a.HashedPassword, peppered)
+}
+
+// PasswordOutdated reports whether the password of a was hashed with
+// another algorithm, parameters or pepper than are used now, it should be
+// hashed again the next time the password is known.
+func (a *Account) PasswordOutdated() bool {
+	return hashID(a.HashedPassword) != PasswordHasher.ID() ||
+		PasswordHasher.Outdated(a.HashedPassword) ||
+		a.PepperID != currentPepperID()
+}
+
+var dummyHash struct {
+	sync.Mutex
+	Account
+}
+
+// CompareDummyPassword takes as long as comparing password against a real
+// hash, without an account to compare it against. Use it when there isn't
+// one so response times don't give away which emails are registered. The
+// dummy hash is made by PasswordHasher with the current pepper and made
+// again whenever either changes, so comparing it costs what comparing a
+// current hash does.
+func CompareDummyPassword(password string) {
+	dummyHash.Lock()
+	if dummyHash.HashedPassword == "" || dummyHash.PasswordOutdated() {
+		err := dummyHash.HashPassword("not a password")
+		if err != nil {
+			dummyHash.HashedPassword = ""
+		}
+	}
+	dummy := dummyHash.Account
+	dummyHash.Unlock()
+
+	// hashing takes as long as comparing when there's no hash to compare
+	if dummy.HashedPassword == "" {
+		PasswordHasher.Hash(password)
+		return
+	}
+
+	dummy.ComparePasswordToHash(password)
 }
 
 func EmailExists(db Database, a *Account) error {
@@ -84,17 +394,72 @@ func EmailExists(db Database, a *Account) error {
 	return nil
 }
 
+// emailInUse returns ErrEmailExists when an account other than the one with
+// the given ID has email
+func emailInUse(db accountReader, ID, email string) error {
+	a, err := db.ReadByEmail(email)
+	if err != nil && err != ErrAccountNotFound {
+		return err
+	}
+
+	if a != nil && a.ID != ID {
+		return ErrEmailExists
+	}
+
+	return nil
+}
+
 func DatabaseFromEnv() Database {
 	var conn Database
 	var err error
 
+	pageSizesFromEnv()
+	tokenTTLFromEnv()
+	sessionTTLsFromEnv()
+	mfaTTLFromEnv()
+	lockoutFromEnv()
+	loginLinksFromEnv()
+
+	err = passwordPolicyFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	err = hasherFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	err = peppersFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	if os.Getenv("ACCOUNT_DB") == "memory" {
+		conn = &Memory{}
+	}
+
 	pg := os.Getenv("POSTGRESQL_URL")
-	if pg != "" {
+	if conn == nil && pg != "" {
 		pgConn := &PostgreSQL{}
 		err = pgConn.Connect(pg)
 		conn = pgConn
 	}
 
+	my := os.Getenv("MYSQL_URL")
+	if conn == nil && my != "" {
+		myConn := &MySQL{}
+		err = myConn.Connect(my)
+		conn = myConn
+	}
+
+	sqlite := os.Getenv("SQLITE_PATH")
+	if conn == nil && sqlite != "" {
+		sqliteConn := &SQLite{}
+		err = sqliteConn.Connect(sqlite)
+		conn = sqliteConn
+	}
+
 	if conn == nil {
 		panic(ErrNoDatabase)
 	}
@@ -105,3 +470,17 @@ func DatabaseFromEnv() Database {
 
 	return conn
 }
+
+func tokenTTLFromEnv() {
+	if d, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && d > 0 {
+		PasswordResetTTL = d
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("CONFIRMATION_TTL")); err == nil && d > 0 {
+		ConfirmationTTL = d
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("EMAIL_CHANGE_TTL")); err == nil && d > 0 {
+		EmailChangeTTL = d
+	}
+}
@@ -0,0 +1,1474 @@
+// Package dbtest provides a conformance test suite that every
+// database.Database implementation is expected to pass.
+package dbtest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lileio/account_service/database"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Factory returns the Database under test. RunConformance truncates it before
+// every test so it can return the same connection each time.
+type Factory func(t *testing.T) database.Database
+
+// RunConformance runs every method of the database.Database interface
+// against the implementation returned by factory.
+func RunConformance(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, db database.Database)
+	}{
+		{"CreateAndRead", testCreateAndRead},
+		{"CreateDuplicateEmail", testCreateDuplicateEmail},
+		{"ReadNotFound", testReadNotFound},
+		{"EmailIgnoresCase", testEmailIgnoresCase},
+		{"Update", testUpdate},
+		{"UpdateFields", testUpdateFields},
+		{"UpdateMetadata", testUpdateMetadata},
+		{"UpdateDuplicateEmail", testUpdateDuplicateEmail},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"UpdateVersion", testUpdateVersion},
+		{"Delete", testDelete},
+		{"DeleteNotFound", testDeleteNotFound},
+		{"DeleteVersion", testDeleteVersion},
+		{"WritesIncrementVersion", testWritesIncrementVersion},
+		{"WritesSetUpdatedAt", testWritesSetUpdatedAt},
+		{"RecordLogin", testRecordLogin},
+		{"List", testList},
+		{"ListPages", testListPages},
+		{"ListExactPage", testListExactPage},
+		{"ListPageSize", testListPageSize},
+		{"ListLegacyToken", testListLegacyToken},
+		{"ListInvalidToken", testListInvalidToken},
+		{"ListFilters", testListFilters},
+		{"ListFilterPages", testListFilterPages},
+		{"ListOrder", testListOrder},
+		{"ListInvalidOrder", testListInvalidOrder},
+		{"Confirm", testConfirm},
+		{"ConfirmExpired", testConfirmExpired},
+		{"ConfirmationToken", testConfirmationToken},
+		{"PasswordToken", testPasswordToken},
+		{"PasswordTokenExpired", testPasswordTokenExpired},
+		{"PepperID", testPepperID},
+		{"EmailChange", testEmailChange},
+		{"EmailChangeExpired", testEmailChangeExpired},
+		{"EmailChangeTaken", testEmailChangeTaken},
+		{"PasswordResetCancelsEmailChange", testPasswordResetCancelsEmailChange},
+		{"Metadata", testMetadata},
+		{"Sessions", testSessions},
+		{"RefreshSession", testRefreshSession},
+		{"RefreshSessionExpired", testRefreshSessionExpired},
+		{"TouchSession", testTouchSession},
+		{"TouchSessionExpired", testTouchSessionExpired},
+		{"RevokeSession", testRevokeSession},
+		{"RevokeAllSessions", testRevokeAllSessions},
+		{"DeleteRevokesSessions", testDeleteRevokesSessions},
+		{"Mfa", testMfa},
+		{"MfaEnrolEnabled", testMfaEnrolEnabled},
+		{"MfaSteps", testMfaSteps},
+		{"MfaRecoveryCodes", testMfaRecoveryCodes},
+		{"MfaChallenge", testMfaChallenge},
+		{"MfaChallengeExpired", testMfaChallengeExpired},
+		{"DeleteRemovesMfa", testDeleteRemovesMfa},
+		{"LoginFailures", testLoginFailures},
+		{"LoginFailuresBackoff", testLoginFailuresBackoff},
+		{"LoginFailuresWindow", testLoginFailuresWindow},
+		{"LoginLinks", testLoginLinks},
+		{"LoginLinkExpired", testLoginLinkExpired},
+		{"LoginLinkLimit", testLoginLinkLimit},
+		{"LoginLinkLimitConcurrent", testLoginLinkLimitConcurrent},
+		{"LoginLinkWindow", testLoginLinkWindow},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db := factory(t)
+			assert.Nil(t, db.Migrate())
+			assert.Nil(t, db.Truncate())
+			tt.fn(t, db)
+		})
+	}
+}
+
+var emailCount int
+
+func newAccount() *database.Account {
+	emailCount++
+	return &database.Account{
+		Name:     "Alex B",
+		Email:    "alexb" + strconv.Itoa(emailCount) + "@localhost",
+		Metadata: map[string]string{"test": "test"},
+	}
+}
+
+func createAccount(t *testing.T, db database.Database) *database.Account {
+	a := newAccount()
+	assert.Nil(t, a.HashPassword("password"))
+	err := db.Create(a, "password")
+	assert.Nil(t, err)
+	return a
+}
+
+func testCreateAndRead(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.NotEmpty(t, a.ID)
+	assert.NotEmpty(t, a.ConfirmationToken)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ra.ID)
+	assert.Equal(t, a.Name, ra.Name)
+	assert.Equal(t, a.Email, ra.Email)
+	assert.Equal(t, a.HashedPassword, ra.HashedPassword)
+	assert.False(t, ra.CreatedAt.IsZero())
+	assert.WithinDuration(t, ra.CreatedAt, ra.UpdatedAt, time.Millisecond)
+	assert.True(t, ra.ConfirmedAt.IsZero())
+	assert.True(t, ra.LastLoginAt.IsZero())
+
+	// only the digest of the token is stored
+	assert.Empty(t, ra.ConfirmationToken)
+	assert.Equal(t, database.HashToken(a.ConfirmationToken), ra.ConfirmationTokenHash)
+
+	ea, err := db.ReadByEmail(a.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ea.ID)
+}
+
+func testCreateDuplicateEmail(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	a2 := newAccount()
+	a2.Email = a.Email
+	assert.Nil(t, a2.HashPassword("password"))
+	assert.Equal(t, database.ErrEmailExists, db.Create(a2, "password"))
+
+	a3 := newAccount()
+	a3.Email = "ALEXB" + a.Email[len("alexb"):]
+	assert.Nil(t, a3.HashPassword("password"))
+	assert.Equal(t, database.ErrEmailExists, db.Create(a3, "password"))
+}
+
+func testEmailIgnoresCase(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	upper := strings.ToUpper(a.Email)
+
+	ra, err := db.ReadByEmail(upper)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ra.ID)
+	assert.Equal(t, a.Email, ra.Email)
+
+	ta, err := db.GeneratePasswordToken(upper)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ta.ID)
+
+	ca, err := db.GenerateConfirmationToken(upper)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ca.ID)
+}
+
+func testReadNotFound(t *testing.T, db database.Database) {
+	_, err := db.ReadByID(uuid.NewV1().String())
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.ReadByEmail("nobody@localhost")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testUpdate(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	a.Name = "Alex C"
+	a.Email = "somethingnew@localhost"
+	assert.Nil(t, db.Update(a))
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alex C", ra.Name)
+	assert.Equal(t, "somethingnew@localhost", ra.Email)
+	assert.Equal(t, a.HashedPassword, ra.HashedPassword)
+}
+
+func testUpdateFields(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	u := &database.Account{ID: a.ID, Name: "Alex C"}
+	assert.Nil(t, db.Update(u, "name"))
+	assert.Equal(t, "Alex C", u.Name)
+	assert.Equal(t, a.Email, u.Email)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alex C", ra.Name)
+	assert.Equal(t, a.Email, ra.Email)
+	assert.Equal(t, a.Metadata, ra.Metadata)
+
+	u = &database.Account{ID: a.ID}
+	assert.NotNil(t, db.Update(u, "name"))
+
+	u = &database.Account{ID: a.ID, Name: "Alex D"}
+	assert.Equal(t, database.ErrUnknownField, db.Update(u, "confirmation_token"))
+
+	// passwords are only changed when asked for
+	u = &database.Account{ID: a.ID, Name: "Alex D", Email: a.Email, HashedPassword: "newhash"}
+	assert.Nil(t, db.Update(u))
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.HashedPassword, ra.HashedPassword)
+
+	u = &database.Account{ID: a.ID, HashedPassword: "newhash"}
+	assert.Nil(t, db.Update(u, "hashed_password"))
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "newhash", ra.HashedPassword)
+	assert.Equal(t, "Alex D", ra.Name)
+}
+
+func testUpdateMetadata(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	a.Metadata = map[string]string{"plan": "pro"}
+	assert.Nil(t, db.Update(a, "metadata"))
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"plan": "pro"}, ra.Metadata)
+
+	ra.Metadata = map[string]string{"plan": "free"}
+	ra.Name = "Alex C"
+	assert.Nil(t, db.Update(ra))
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alex C", ra.Name)
+	assert.Equal(t, map[string]string{"plan": "free"}, ra.Metadata)
+}
+
+func testUpdateDuplicateEmail(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	a2 := createAccount(t, db)
+
+	a2.Email = "ALEXB" + a.Email[len("alexb"):]
+	assert.Equal(t, database.ErrEmailExists, db.Update(a2))
+}
+
+func testUpdateNotFound(t *testing.T, db database.Database) {
+	a := newAccount()
+	a.ID = uuid.NewV1().String()
+	assert.Equal(t, database.ErrAccountNotFound, db.Update(a))
+}
+
+func testUpdateVersion(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Version, ra.Version)
+
+	a.Name = "Alex C"
+	assert.Nil(t, db.Update(a))
+	assert.Equal(t, ra.Version+1, a.Version)
+
+	// ra is now stale
+	ra.Name = "Alex D"
+	assert.Equal(t, database.ErrVersionConflict, db.Update(ra))
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alex C", ra.Name)
+	assert.Equal(t, a.Version, ra.Version)
+
+	// a version of 0 skips the check
+	ra.Name = "Alex D"
+	ra.Version = 0
+	assert.Nil(t, db.Update(ra))
+	assert.Equal(t, a.Version+1, ra.Version)
+}
+
+func testDelete(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	assert.Nil(t, db.Delete(a.ID, 0))
+
+	_, err := db.ReadByID(a.ID)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testDeleteNotFound(t *testing.T, db database.Database) {
+	assert.Nil(t, db.Delete(uuid.NewV1().String(), 0))
+	assert.Equal(t, database.ErrAccountNotFound, db.Delete(uuid.NewV1().String(), 1))
+}
+
+func testDeleteVersion(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	assert.Equal(t, database.ErrVersionConflict, db.Delete(a.ID, a.Version+1))
+
+	_, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Delete(a.ID, a.Version))
+
+	_, err = db.ReadByID(a.ID)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testWritesIncrementVersion(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Equal(t, int64(1), a.Version)
+
+	ca, err := db.Confirm(a.ConfirmationToken)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), ca.Version)
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), ta.Version)
+
+	ua, err := db.UpdatePassword(ta.PasswordResetToken, "newhash", "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), ua.Version)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), ra.Version)
+
+	assert.Nil(t, db.Update(ra, "name"))
+	assert.Equal(t, int64(5), ra.Version)
+}
+
+func testWritesSetUpdatedAt(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.False(t, a.UpdatedAt.IsZero())
+
+	// each write is checked to have happened no earlier than the last
+	last := a.UpdatedAt
+	written := func(b *database.Account) {
+		assert.False(t, b.UpdatedAt.Before(last))
+		last = b.UpdatedAt
+
+		ra, err := db.ReadByID(a.ID)
+		assert.Nil(t, err)
+		assert.WithinDuration(t, b.UpdatedAt, ra.UpdatedAt, time.Millisecond)
+	}
+
+	ca, err := db.Confirm(a.ConfirmationToken)
+	assert.Nil(t, err)
+	written(ca)
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+	written(ta)
+
+	ua, err := db.UpdatePassword(ta.PasswordResetToken, "newhash", "")
+	assert.Nil(t, err)
+	written(ua)
+
+	ea, err := db.RequestEmailChange(a.ID, "changed"+a.Email)
+	assert.Nil(t, err)
+	written(ea)
+
+	ea, err = db.ConfirmEmailChange(ea.EmailChangeToken)
+	assert.Nil(t, err)
+	written(ea)
+
+	assert.Nil(t, db.Update(ea, "name"))
+	written(ea)
+
+	b := createAccount(t, db)
+	ba, err := db.GenerateConfirmationToken(b.Email)
+	assert.Nil(t, err)
+	assert.False(t, ba.UpdatedAt.Before(b.UpdatedAt))
+}
+
+func testRecordLogin(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	assert.Nil(t, db.RecordLogin(a))
+	assert.WithinDuration(t, time.Now(), a.LastLoginAt, time.Minute)
+
+	// logging in isn't a write to the account
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, a.LastLoginAt, ra.LastLoginAt, time.Millisecond)
+	assert.Equal(t, a.Version, ra.Version)
+	assert.WithinDuration(t, a.UpdatedAt, ra.UpdatedAt, time.Millisecond)
+
+	err = db.RecordLogin(&database.Account{ID: uuid.NewV1().String()})
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testList(t *testing.T, db database.Database) {
+	for i := 0; i < 3; i++ {
+		createAccount(t, db)
+	}
+
+	accounts, token, err := db.List(5, "", database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 3)
+	assert.Empty(t, token)
+}
+
+func testListPages(t *testing.T, db database.Database) {
+	created := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		created[createAccount(t, db).ID] = true
+	}
+
+	seen := map[string]bool{}
+	token := ""
+	for pages := 0; pages < 5; pages++ {
+		accounts, next, err := db.List(2, token, database.ListOptions{})
+		assert.Nil(t, err)
+		assert.True(t, len(accounts) <= 2)
+
+		for _, a := range accounts {
+			assert.False(t, seen[a.ID], "account returned twice")
+			seen[a.ID] = true
+		}
+
+		if next == "" {
+			assert.Equal(t, created, seen)
+			return
+		}
+
+		assert.NotEqual(t, token, next)
+		token = next
+	}
+
+	t.Fatal("next page token was never empty")
+}
+
+func testListExactPage(t *testing.T, db database.Database) {
+	for i := 0; i < 4; i++ {
+		createAccount(t, db)
+	}
+
+	accounts, token, err := db.List(2, "", database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 2)
+	assert.NotEmpty(t, token)
+
+	accounts, token, err = db.List(2, token, database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Empty(t, token)
+}
+
+func testListPageSize(t *testing.T, db database.Database) {
+	for i := 0; i < 3; i++ {
+		createAccount(t, db)
+	}
+
+	accounts, token, err := db.List(0, "", database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 3)
+	assert.Empty(t, token)
+
+	max := database.MaxPageSize
+	database.MaxPageSize = 2
+	defer func() { database.MaxPageSize = max }()
+
+	accounts, token, err = db.List(10, "", database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 2)
+	assert.NotEmpty(t, token)
+}
+
+func testListLegacyToken(t *testing.T, db database.Database) {
+	for i := 0; i < 3; i++ {
+		createAccount(t, db)
+	}
+
+	all, _, err := db.List(3, "", database.ListOptions{})
+	assert.Nil(t, err)
+
+	accounts, token, err := db.List(1, "1", database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, all[1].ID, accounts[0].ID)
+
+	accounts, token, err = db.List(1, token, database.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, all[2].ID, accounts[0].ID)
+	assert.Empty(t, token)
+}
+
+func testListInvalidToken(t *testing.T, db database.Database) {
+	_, _, err := db.List(2, "notatoken", database.ListOptions{})
+	assert.Equal(t, database.ErrInvalidPageToken, err)
+
+	_, _, err = db.List(2, "-1", database.ListOptions{})
+	assert.Equal(t, database.ErrInvalidPageToken, err)
+}
+
+func listIDs(accounts []*database.Account) []string {
+	ids := []string{}
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func testListFilters(t *testing.T, db database.Database) {
+	a1 := newAccount()
+	a1.Name = "Jane Smith"
+	a1.Email = "jane@example.com"
+	a1.Metadata = map[string]string{"plan": "pro", "team": "a"}
+
+	a2 := newAccount()
+	a2.Name = "John Smithson"
+	a2.Email = "john@example.com"
+	a2.Metadata = map[string]string{"plan": "pro", "team": "b"}
+
+	a3 := newAccount()
+	a3.Name = "Alex B"
+	a3.Email = "JAMES@localhost"
+	a3.Metadata = map[string]string{"plan": "free"}
+
+	for _, a := range []*database.Account{a1, a2, a3} {
+		assert.Nil(t, a.HashPassword("password"))
+		assert.Nil(t, db.Create(a, "password"))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, err := db.Confirm(a2.ConfirmationToken)
+	assert.Nil(t, err)
+
+	confirmed, unconfirmed := true, false
+
+	tests := []struct {
+		opts database.ListOptions
+		ids  []string
+	}{
+		{database.ListOptions{EmailPrefix: "JA"}, []string{a1.ID, a3.ID}},
+		{database.ListOptions{EmailPrefix: "j%"}, []string{}},
+		{database.ListOptions{NameContains: "smith"}, []string{a1.ID, a2.ID}},
+		{database.ListOptions{NameContains: "SMITHSON"}, []string{a2.ID}},
+		{database.ListOptions{CreatedAfter: a1.CreatedAt}, []string{a2.ID, a3.ID}},
+		{database.ListOptions{CreatedBefore: a3.CreatedAt}, []string{a1.ID, a2.ID}},
+		{database.ListOptions{CreatedAfter: a1.CreatedAt, CreatedBefore: a3.CreatedAt}, []string{a2.ID}},
+		{database.ListOptions{Confirmed: &confirmed}, []string{a2.ID}},
+		{database.ListOptions{Confirmed: &unconfirmed}, []string{a1.ID, a3.ID}},
+		{database.ListOptions{Metadata: map[string]string{"plan": "pro"}}, []string{a1.ID, a2.ID}},
+		{database.ListOptions{Metadata: map[string]string{"plan": "pro", "team": "b"}}, []string{a2.ID}},
+		{database.ListOptions{Metadata: map[string]string{"missing": "pro"}}, []string{}},
+		{database.ListOptions{NameContains: "smith", Confirmed: &unconfirmed}, []string{a1.ID}},
+	}
+
+	for _, tt := range tests {
+		accounts, token, err := db.List(10, "", tt.opts)
+		assert.Nil(t, err)
+		assert.Empty(t, token)
+		assert.Equal(t, tt.ids, listIDs(accounts), "%+v", tt.opts)
+	}
+}
+
+func testListFilterPages(t *testing.T, db database.Database) {
+	ids := []string{}
+	for i := 0; i < 6; i++ {
+		a := newAccount()
+		if i%2 == 0 {
+			a.Metadata = map[string]string{"even": "yes"}
+		}
+
+		assert.Nil(t, a.HashPassword("password"))
+		assert.Nil(t, db.Create(a, "password"))
+
+		if i%2 == 0 {
+			ids = append(ids, a.ID)
+		}
+	}
+
+	opts := database.ListOptions{Metadata: map[string]string{"even": "yes"}, OrderBy: "email desc"}
+	seen := []string{}
+	token := ""
+	for {
+		accounts, next, err := db.List(2, token, opts)
+		assert.Nil(t, err)
+		seen = append(seen, listIDs(accounts)...)
+
+		if next == "" {
+			break
+		}
+
+		token = next
+	}
+
+	sort.Strings(ids)
+	sort.Strings(seen)
+	assert.Equal(t, ids, seen)
+
+	first, token, err := db.List(1, "", opts)
+	assert.Nil(t, err)
+	assert.Len(t, first, 1)
+
+	_, _, err = db.List(1, token, database.ListOptions{OrderBy: "email desc"})
+	assert.Equal(t, database.ErrInvalidPageToken, err)
+}
+
+func testListOrder(t *testing.T, db database.Database) {
+	names := []string{"Charlie", "Alice", "Bob"}
+	for _, n := range names {
+		a := newAccount()
+		a.Name = n
+		assert.Nil(t, a.HashPassword("password"))
+		assert.Nil(t, db.Create(a, "password"))
+	}
+
+	tests := []struct {
+		order string
+		names []string
+	}{
+		{"", []string{"Charlie", "Alice", "Bob"}},
+		{"created_at desc", []string{"Bob", "Alice", "Charlie"}},
+		{"name", []string{"Alice", "Bob", "Charlie"}},
+		{"name DESC", []string{"Charlie", "Bob", "Alice"}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		token := ""
+		for {
+			accounts, next, err := db.List(1, token, database.ListOptions{OrderBy: tt.order})
+			assert.Nil(t, err)
+
+			for _, a := range accounts {
+				got = append(got, a.Name)
+			}
+
+			if next == "" {
+				break
+			}
+
+			token = next
+		}
+
+		assert.Equal(t, tt.names, got, tt.order)
+	}
+}
+
+func testListInvalidOrder(t *testing.T, db database.Database) {
+	for _, o := range []string{"hashed_password", "name sideways", "name asc desc"} {
+		_, _, err := db.List(2, "", database.ListOptions{OrderBy: o})
+		assert.Equal(t, database.ErrInvalidOrderBy, err, o)
+	}
+}
+
+func testConfirm(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.WithinDuration(t, time.Now().Add(database.ConfirmationTTL), a.ConfirmationExpiresAt, time.Minute)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, a.ConfirmationExpiresAt, ra.ConfirmationExpiresAt, time.Millisecond)
+
+	ca, err := db.Confirm(a.ConfirmationToken)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ca.ID)
+	assert.Empty(t, ca.ConfirmationToken)
+	assert.WithinDuration(t, time.Now(), ca.ConfirmedAt, time.Minute)
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, ra.ConfirmationTokenHash)
+	assert.True(t, ra.ConfirmationExpiresAt.IsZero())
+	assert.WithinDuration(t, ca.ConfirmedAt, ra.ConfirmedAt, time.Millisecond)
+
+	_, err = db.Confirm(a.ConfirmationToken)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	a2 := createAccount(t, db)
+	ra2, err := db.ReadByID(a2.ID)
+	assert.Nil(t, err)
+
+	_, err = db.Confirm(ra2.ConfirmationTokenHash)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.Confirm("")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testConfirmExpired(t *testing.T, db database.Database) {
+	ttl := database.ConfirmationTTL
+	database.ConfirmationTTL = -time.Minute
+	defer func() { database.ConfirmationTTL = ttl }()
+
+	a := createAccount(t, db)
+
+	_, err := db.Confirm(a.ConfirmationToken)
+	assert.Equal(t, database.ErrTokenExpired, err)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.False(t, ra.Confirmed())
+}
+
+func testConfirmationToken(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ta, err := db.GenerateConfirmationToken(a.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ta.ID)
+	assert.NotEmpty(t, ta.ConfirmationToken)
+	assert.NotEqual(t, a.ConfirmationToken, ta.ConfirmationToken)
+	assert.WithinDuration(t, time.Now().Add(database.ConfirmationTTL), ta.ConfirmationExpiresAt, time.Minute)
+	assert.Equal(t, a.Version+1, ta.Version)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, database.HashToken(ta.ConfirmationToken), ra.ConfirmationTokenHash)
+
+	// the old token is replaced
+	_, err = db.Confirm(a.ConfirmationToken)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.Confirm(ta.ConfirmationToken)
+	assert.Nil(t, err)
+
+	_, err = db.GenerateConfirmationToken(a.Email)
+	assert.Equal(t, database.ErrConfirmed, err)
+
+	_, err = db.GenerateConfirmationToken("nobody@localhost")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testPasswordToken(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	a2 := createAccount(t, db)
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, ta.PasswordResetToken)
+	assert.WithinDuration(t, time.Now().Add(database.PasswordResetTTL), ta.PasswordResetExpiresAt, time.Minute)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, ta.PasswordResetExpiresAt, ra.PasswordResetExpiresAt, time.Millisecond)
+	assert.Empty(t, ra.PasswordResetToken)
+	assert.Equal(t, database.HashToken(ta.PasswordResetToken), ra.PasswordResetTokenHash)
+
+	// the digest can't be used in place of the token
+	_, err = db.UpdatePassword(ra.PasswordResetTokenHash, "newhash", "")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	ta2, err := db.GeneratePasswordToken(a2.Email)
+	assert.Nil(t, err)
+	assert.NotEqual(t, ta.PasswordResetToken, ta2.PasswordResetToken)
+
+	_, err = db.GeneratePasswordToken("nobody@localhost")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	ra, err = db.ReadByPasswordToken(ta.PasswordResetToken)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ra.ID)
+	assert.Equal(t, a.Email, ra.Email)
+
+	_, err = db.ReadByPasswordToken(ra.PasswordResetTokenHash)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	ua, err := db.UpdatePassword(ta.PasswordResetToken, "newhash", "")
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ua.ID)
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "newhash", ra.HashedPassword)
+	assert.Empty(t, ra.PasswordResetTokenHash)
+	assert.True(t, ra.PasswordResetExpiresAt.IsZero())
+
+	// tokens can only be used once
+	_, err = db.UpdatePassword(ta.PasswordResetToken, "otherhash", "")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	ra2, err := db.ReadByID(a2.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a2.HashedPassword, ra2.HashedPassword)
+
+	_, err = db.UpdatePassword("notatoken", "newhash", "")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.UpdatePassword("", "newhash", "")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testPasswordTokenExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ttl := database.PasswordResetTTL
+	database.PasswordResetTTL = -time.Minute
+	defer func() { database.PasswordResetTTL = ttl }()
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+
+	_, err = db.UpdatePassword(ta.PasswordResetToken, "newhash", "")
+	assert.Equal(t, database.ErrTokenExpired, err)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.HashedPassword, ra.HashedPassword)
+}
+
+func testPepperID(t *testing.T, db database.Database) {
+	a := newAccount()
+	a.HashedPassword = "hash"
+	a.PepperID = "pepper-1"
+	assert.Nil(t, db.Create(a, "password"))
+
+	ra, err := db.ReadByEmail(a.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, "pepper-1", ra.PepperID)
+
+	// the pepper changes along with the password
+	u := &database.Account{ID: a.ID, HashedPassword: "newhash", PepperID: "pepper-2"}
+	assert.Nil(t, db.Update(u, "hashed_password"))
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "newhash", ra.HashedPassword)
+	assert.Equal(t, "pepper-2", ra.PepperID)
+
+	ra.Name = "Alex C"
+	assert.Nil(t, db.Update(ra, "name"))
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "pepper-2", ra.PepperID)
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+	ua, err := db.UpdatePassword(ta.PasswordResetToken, "resethash", "")
+	assert.Nil(t, err)
+	assert.Empty(t, ua.PepperID)
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "resethash", ra.HashedPassword)
+	assert.Empty(t, ra.PepperID)
+}
+
+func testEmailChange(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ca, err := db.RequestEmailChange(a.ID, "changed@localhost")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, ca.EmailChangeToken)
+	assert.Equal(t, a.Email, ca.Email)
+	assert.Equal(t, "changed@localhost", ca.PendingEmail)
+	assert.WithinDuration(t, time.Now().Add(database.EmailChangeTTL), ca.EmailChangeExpiresAt, time.Minute)
+	assert.Equal(t, a.Version+1, ca.Version)
+
+	// nothing changes until it's confirmed
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Email, ra.Email)
+	assert.Equal(t, "changed@localhost", ra.PendingEmail)
+	assert.Equal(t, database.HashToken(ca.EmailChangeToken), ra.EmailChangeTokenHash)
+	assert.WithinDuration(t, ca.EmailChangeExpiresAt, ra.EmailChangeExpiresAt, time.Millisecond)
+
+	_, err = db.ConfirmEmailChange(ra.EmailChangeTokenHash)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	ua, err := db.ConfirmEmailChange(ca.EmailChangeToken)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ua.ID)
+	assert.Equal(t, "changed@localhost", ua.Email)
+	assert.Equal(t, a.Email, ua.PreviousEmail)
+	assert.Empty(t, ua.PendingEmail)
+
+	ra, err = db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "changed@localhost", ra.Email)
+	assert.Empty(t, ra.PendingEmail)
+	assert.Empty(t, ra.EmailChangeTokenHash)
+	assert.True(t, ra.EmailChangeExpiresAt.IsZero())
+	assert.Equal(t, ca.Version+1, ra.Version)
+
+	// tokens can only be used once
+	_, err = db.ConfirmEmailChange(ca.EmailChangeToken)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.ConfirmEmailChange("")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+
+	_, err = db.RequestEmailChange(uuid.NewV1().String(), "other@localhost")
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testEmailChangeExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ttl := database.EmailChangeTTL
+	database.EmailChangeTTL = -time.Minute
+	defer func() { database.EmailChangeTTL = ttl }()
+
+	ca, err := db.RequestEmailChange(a.ID, "changed@localhost")
+	assert.Nil(t, err)
+
+	_, err = db.ConfirmEmailChange(ca.EmailChangeToken)
+	assert.Equal(t, database.ErrTokenExpired, err)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Email, ra.Email)
+}
+
+func testEmailChangeTaken(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	a2 := createAccount(t, db)
+
+	_, err := db.RequestEmailChange(a.ID, a2.Email)
+	assert.Equal(t, database.ErrEmailExists, err)
+
+	ca, err := db.RequestEmailChange(a.ID, "changed@localhost")
+	assert.Nil(t, err)
+
+	// taken by another account before it was confirmed
+	a2.Email = "changed@localhost"
+	assert.Nil(t, db.Update(a2, "email"))
+
+	_, err = db.ConfirmEmailChange(ca.EmailChangeToken)
+	assert.Equal(t, database.ErrEmailExists, err)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Email, ra.Email)
+}
+
+func testPasswordResetCancelsEmailChange(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ca, err := db.RequestEmailChange(a.ID, "changed@localhost")
+	assert.Nil(t, err)
+
+	ta, err := db.GeneratePasswordToken(a.Email)
+	assert.Nil(t, err)
+
+	ua, err := db.UpdatePassword(ta.PasswordResetToken, "newhash", "")
+	assert.Nil(t, err)
+	assert.Empty(t, ua.PendingEmail)
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, ra.PendingEmail)
+	assert.Empty(t, ra.EmailChangeTokenHash)
+
+	_, err = db.ConfirmEmailChange(ca.EmailChangeToken)
+	assert.Equal(t, database.ErrAccountNotFound, err)
+}
+
+func testMetadata(t *testing.T, db database.Database) {
+	a := newAccount()
+	a.Metadata = map[string]string{"plan": "pro", "source": "signup"}
+	assert.Nil(t, a.HashPassword("password"))
+	assert.Nil(t, db.Create(a, "password"))
+
+	ra, err := db.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Metadata, ra.Metadata)
+
+	ea, err := db.ReadByEmail(a.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Metadata, ea.Metadata)
+}
+
+func createSession(t *testing.T, db database.Database, a *database.Account) *database.Session {
+	s := &database.Session{AccountID: a.ID, UserAgent: "curl/7.54", Device: "laptop"}
+	assert.Nil(t, db.CreateSession(s))
+	return s
+}
+
+func testSessions(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	s := createSession(t, db, a)
+	assert.NotEmpty(t, s.ID)
+	assert.NotEmpty(t, s.AccessToken)
+	assert.NotEmpty(t, s.RefreshToken)
+	assert.Equal(t, database.HashToken(s.RefreshToken), s.RefreshTokenHash)
+	assert.True(t, s.AccessTokenExpiresAt.After(time.Now()))
+	assert.True(t, s.RefreshTokenExpiresAt.After(s.AccessTokenExpiresAt))
+
+	other := createSession(t, db, createAccount(t, db))
+
+	sessions, err := db.ListSessions(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, s.ID, sessions[0].ID)
+	assert.Equal(t, "curl/7.54", sessions[0].UserAgent)
+	assert.Equal(t, "laptop", sessions[0].Device)
+	assert.Empty(t, sessions[0].AccessToken)
+	assert.Empty(t, sessions[0].RefreshToken)
+	assert.NotEqual(t, other.ID, sessions[0].ID)
+}
+
+func testRefreshSession(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	s := createSession(t, db, a)
+
+	rs, err := db.RefreshSession(s.RefreshToken)
+	assert.Nil(t, err)
+	assert.Equal(t, s.ID, rs.ID)
+	assert.Equal(t, a.ID, rs.AccountID)
+	assert.NotEmpty(t, rs.RefreshToken)
+	assert.NotEqual(t, s.AccessToken, rs.AccessToken)
+	assert.NotEqual(t, s.RefreshToken, rs.RefreshToken)
+
+	// refresh tokens can only be used once
+	_, err = db.RefreshSession(s.RefreshToken)
+	assert.Equal(t, database.ErrSessionNotFound, err)
+
+	_, err = db.RefreshSession(rs.RefreshToken)
+	assert.Nil(t, err)
+}
+
+func testRefreshSessionExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ttl := database.SessionRefreshTTL
+	database.SessionRefreshTTL = -time.Minute
+	defer func() { database.SessionRefreshTTL = ttl }()
+
+	s := createSession(t, db, a)
+
+	_, err := db.RefreshSession(s.RefreshToken)
+	assert.Equal(t, database.ErrTokenExpired, err)
+
+	// expired sessions aren't listed
+	sessions, err := db.ListSessions(a.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+}
+
+func testTouchSession(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	s := createSession(t, db, a)
+
+	time.Sleep(10 * time.Millisecond)
+	ts, err := db.TouchSession(s.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, s.ID, ts.ID)
+	assert.Equal(t, a.ID, ts.AccountID)
+	assert.Empty(t, ts.AccessToken)
+	assert.True(t, ts.LastSeenAt.After(s.LastSeenAt))
+
+	sessions, err := db.ListSessions(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.True(t, sessions[0].LastSeenAt.Equal(ts.LastSeenAt))
+
+	// refreshing replaces the access token
+	_, err = db.RefreshSession(s.RefreshToken)
+	assert.Nil(t, err)
+	_, err = db.TouchSession(s.AccessToken)
+	assert.Equal(t, database.ErrSessionNotFound, err)
+
+	_, err = db.TouchSession("unknown")
+	assert.Equal(t, database.ErrSessionNotFound, err)
+}
+
+func testTouchSessionExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ttl := database.SessionAccessTTL
+	database.SessionAccessTTL = -time.Minute
+	defer func() { database.SessionAccessTTL = ttl }()
+
+	s := createSession(t, db, a)
+
+	_, err := db.TouchSession(s.AccessToken)
+	assert.Equal(t, database.ErrTokenExpired, err)
+}
+
+func testRevokeSession(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	s := createSession(t, db, a)
+	keep := createSession(t, db, a)
+
+	assert.Nil(t, db.RevokeSession(s.ID))
+	assert.Equal(t, database.ErrSessionNotFound, db.RevokeSession(s.ID))
+
+	_, err := db.RefreshSession(s.RefreshToken)
+	assert.Equal(t, database.ErrSessionNotFound, err)
+
+	sessions, err := db.ListSessions(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, keep.ID, sessions[0].ID)
+}
+
+func testRevokeAllSessions(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	createSession(t, db, a)
+	createSession(t, db, a)
+	other := createSession(t, db, createAccount(t, db))
+
+	assert.Nil(t, db.RevokeAllSessions(a.ID))
+
+	sessions, err := db.ListSessions(a.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+
+	sessions, err = db.ListSessions(other.AccountID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions))
+}
+
+func testDeleteRevokesSessions(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	s := createSession(t, db, a)
+
+	assert.Nil(t, db.Delete(a.ID, 0))
+
+	_, err := db.RefreshSession(s.RefreshToken)
+	assert.Equal(t, database.ErrSessionNotFound, err)
+}
+
+func testMfa(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	_, err := db.ReadMfa(a.ID)
+	assert.Equal(t, database.ErrMfaNotFound, err)
+	assert.Equal(t, database.ErrMfaNotFound, db.EnableMfa(a.ID, 1))
+	assert.Equal(t, database.ErrMfaNotFound, db.DisableMfa(a.ID))
+
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET1"))
+	// enrolling again replaces an enrolment that isn't enabled
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET2"))
+
+	m, err := db.ReadMfa(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, m.AccountID)
+	assert.Equal(t, "SECRET2", m.Secret)
+	assert.False(t, m.Enabled)
+
+	assert.Nil(t, db.EnableMfa(a.ID, 100))
+	assert.Equal(t, database.ErrMfaEnabled, db.EnableMfa(a.ID, 101))
+
+	m, err = db.ReadMfa(a.ID)
+	assert.Nil(t, err)
+	assert.True(t, m.Enabled)
+	assert.Equal(t, int64(100), m.LastUsedStep)
+
+	assert.Nil(t, db.DisableMfa(a.ID))
+	_, err = db.ReadMfa(a.ID)
+	assert.Equal(t, database.ErrMfaNotFound, err)
+}
+
+func testMfaEnrolEnabled(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET1"))
+	assert.Nil(t, db.EnableMfa(a.ID, 1))
+
+	assert.Equal(t, database.ErrMfaEnabled, db.EnrolMfa(a.ID, "SECRET2"))
+
+	m, err := db.ReadMfa(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "SECRET1", m.Secret)
+}
+
+func testMfaSteps(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET"))
+	assert.Nil(t, db.EnableMfa(a.ID, 100))
+
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseMfaStep(a.ID, 100))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseMfaStep(a.ID, 99))
+	assert.Nil(t, db.UseMfaStep(a.ID, 101))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseMfaStep(a.ID, 101))
+
+	assert.Equal(t, database.ErrMfaNotFound, db.UseMfaStep("missing", 1))
+}
+
+func testMfaRecoveryCodes(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Equal(t, database.ErrMfaNotFound, db.SetRecoveryCodes(a.ID, []string{"a"}))
+
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET"))
+	assert.Nil(t, db.EnableMfa(a.ID, 1))
+	assert.Nil(t, db.SetRecoveryCodes(a.ID, []string{"a", "b", "c"}))
+
+	m, err := db.ReadMfa(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, m.RecoveryCodeHashes)
+
+	assert.Nil(t, db.UseRecoveryCode(a.ID, "b"))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseRecoveryCode(a.ID, "b"))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseRecoveryCode(a.ID, "d"))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseRecoveryCode(a.ID, ""))
+
+	m, err = db.ReadMfa(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "c"}, m.RecoveryCodeHashes)
+
+	// generating new codes replaces the old ones
+	assert.Nil(t, db.SetRecoveryCodes(a.ID, []string{"e"}))
+	assert.Equal(t, database.ErrMfaCodeUsed, db.UseRecoveryCode(a.ID, "a"))
+	assert.Nil(t, db.UseRecoveryCode(a.ID, "e"))
+}
+
+func testMfaChallenge(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET"))
+	assert.Nil(t, db.EnableMfa(a.ID, 1))
+
+	m, err := db.CreateMfaChallenge(a.ID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, m.Challenge)
+	assert.Equal(t, database.HashToken(m.Challenge), m.ChallengeHash)
+	assert.True(t, m.ChallengeExpiresAt.After(time.Now()))
+
+	cm, err := db.ConsumeMfaChallenge(m.Challenge)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, cm.AccountID)
+	assert.Equal(t, "SECRET", cm.Secret)
+	assert.Empty(t, cm.Challenge)
+
+	// challenges can only be used once
+	_, err = db.ConsumeMfaChallenge(m.Challenge)
+	assert.Equal(t, database.ErrNoMfaChallenge, err)
+
+	_, err = db.ConsumeMfaChallenge("")
+	assert.Equal(t, database.ErrNoMfaChallenge, err)
+
+	_, err = db.CreateMfaChallenge("missing")
+	assert.Equal(t, database.ErrMfaNotFound, err)
+}
+
+func testMfaChallengeExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET"))
+	assert.Nil(t, db.EnableMfa(a.ID, 1))
+
+	ttl := database.MfaChallengeTTL
+	database.MfaChallengeTTL = -time.Minute
+	defer func() { database.MfaChallengeTTL = ttl }()
+
+	m, err := db.CreateMfaChallenge(a.ID)
+	assert.Nil(t, err)
+
+	_, err = db.ConsumeMfaChallenge(m.Challenge)
+	assert.Equal(t, database.ErrTokenExpired, err)
+}
+
+func testDeleteRemovesMfa(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+	assert.Nil(t, db.EnrolMfa(a.ID, "SECRET"))
+
+	assert.Nil(t, db.Delete(a.ID, 0))
+
+	_, err := db.ReadMfa(a.ID)
+	assert.Equal(t, database.ErrMfaNotFound, err)
+}
+
+func testLoginFailures(t *testing.T, db database.Database) {
+	subject := database.EmailSubject("Alex@localhost")
+	assert.Equal(t, "email:alex@localhost", subject)
+
+	f, err := db.ReadLoginFailures(subject)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, f.Failures)
+	locked, _ := f.Locked()
+	assert.False(t, locked)
+
+	for i := 1; i < database.LockoutThreshold; i++ {
+		f, err = db.RecordLoginFailure(subject)
+		assert.Nil(t, err)
+		assert.Equal(t, i, f.Failures)
+		locked, _ = f.Locked()
+		assert.False(t, locked)
+	}
+
+	f, err = db.RecordLoginFailure(subject)
+	assert.Nil(t, err)
+	locked, d := f.Locked()
+	assert.True(t, locked)
+	assert.True(t, d <= database.LockoutBase)
+	assert.True(t, d > database.LockoutBase-time.Minute)
+
+	f, err = db.ReadLoginFailures(subject)
+	assert.Nil(t, err)
+	assert.Equal(t, database.LockoutThreshold, f.Failures)
+	locked, _ = f.Locked()
+	assert.True(t, locked)
+
+	other := database.AccountSubject("id")
+	_, err = db.RecordLoginFailure(other)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.ResetLoginFailures(subject, other))
+
+	f, err = db.ReadLoginFailures(subject)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, f.Failures)
+	locked, _ = f.Locked()
+	assert.False(t, locked)
+
+	f, err = db.ReadLoginFailures(other)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, f.Failures)
+}
+
+func testLoginFailuresBackoff(t *testing.T, db database.Database) {
+	threshold := database.LockoutThreshold
+	database.LockoutThreshold = 2
+	defer func() { database.LockoutThreshold = threshold }()
+
+	subject := database.AccountSubject("id")
+	durations := []time.Duration{}
+	for i := 0; i < 10; i++ {
+		f, err := db.RecordLoginFailure(subject)
+		assert.Nil(t, err)
+
+		_, d := f.Locked()
+		durations = append(durations, d)
+	}
+
+	assert.True(t, durations[0] <= 0)
+	assert.True(t, durations[1] > database.LockoutBase-time.Minute)
+	assert.True(t, durations[2] > 2*database.LockoutBase-time.Minute)
+	assert.True(t, durations[3] > 4*database.LockoutBase-time.Minute)
+	assert.True(t, durations[9] <= database.LockoutMax)
+	assert.True(t, durations[9] > database.LockoutMax-time.Minute)
+}
+
+func testLoginFailuresWindow(t *testing.T, db database.Database) {
+	window := database.LockoutWindow
+	database.LockoutWindow = -time.Minute
+	defer func() { database.LockoutWindow = window }()
+
+	subject := database.AccountSubject("id")
+	for i := 0; i < database.LockoutThreshold; i++ {
+		f, err := db.RecordLoginFailure(subject)
+		assert.Nil(t, err)
+
+		// every failure is outside the window of the previous one
+		assert.Equal(t, 1, f.Failures)
+	}
+}
+
+func testLoginLinks(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	l, err := db.CreateLoginLink(strings.ToUpper(a.Email), a.ID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, l.Token)
+	assert.Equal(t, database.HashToken(l.Token), l.TokenHash)
+	assert.True(t, l.ExpiresAt.After(time.Now()))
+
+	used, err := db.ConsumeLoginLink(l.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, used.AccountID)
+	assert.Equal(t, a.Email, used.Email)
+	assert.False(t, used.UsedAt.IsZero())
+
+	// each link can only be used once
+	_, err = db.ConsumeLoginLink(l.Token)
+	assert.Equal(t, database.ErrNoLoginLink, err)
+
+	_, err = db.ConsumeLoginLink("unknown")
+	assert.Equal(t, database.ErrNoLoginLink, err)
+
+	_, err = db.ConsumeLoginLink("")
+	assert.Equal(t, database.ErrNoLoginLink, err)
+
+	// links for emails without an account never log in
+	l, err = db.CreateLoginLink("nobody@localhost", "")
+	assert.Nil(t, err)
+
+	_, err = db.ConsumeLoginLink(l.Token)
+	assert.Equal(t, database.ErrNoLoginLink, err)
+}
+
+func testLoginLinkExpired(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	ttl := database.LoginLinkTTL
+	database.LoginLinkTTL = -time.Minute
+	defer func() { database.LoginLinkTTL = ttl }()
+
+	l, err := db.CreateLoginLink(a.Email, a.ID)
+	assert.Nil(t, err)
+
+	_, err = db.ConsumeLoginLink(l.Token)
+	assert.Equal(t, database.ErrTokenExpired, err)
+}
+
+func testLoginLinkLimit(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	for i := 0; i < database.LoginLinkLimit; i++ {
+		_, err := db.CreateLoginLink(a.Email, a.ID)
+		assert.Nil(t, err)
+	}
+
+	// emails are limited however they're written
+	_, err := db.CreateLoginLink(strings.ToUpper(a.Email), a.ID)
+	limited, ok := err.(*database.RateLimitError)
+	if assert.True(t, ok, "expected a rate limit error, got %v", err) {
+		assert.True(t, limited.RetryAfter > 0)
+		assert.True(t, limited.RetryAfter <= database.LoginLinkWindow)
+	}
+
+	// as are emails without an account
+	for i := 0; i < database.LoginLinkLimit; i++ {
+		_, err = db.CreateLoginLink("nobody@localhost", "")
+		assert.Nil(t, err)
+	}
+
+	_, err = db.CreateLoginLink("nobody@localhost", "")
+	assert.IsType(t, &database.RateLimitError{}, err)
+
+	_, err = db.CreateLoginLink("somebody@localhost", "")
+	assert.Nil(t, err)
+}
+
+func testLoginLinkLimitConcurrent(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*database.LoginLinkLimit)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := db.CreateLoginLink(a.Email, a.ID)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// requests made at once can't all count the links before any is added
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+		}
+	}
+
+	assert.True(t, created > 0)
+	assert.True(t, created <= database.LoginLinkLimit, "%d links created", created)
+}
+
+func testLoginLinkWindow(t *testing.T, db database.Database) {
+	a := createAccount(t, db)
+
+	window := database.LoginLinkWindow
+	database.LoginLinkWindow = -time.Minute
+	defer func() { database.LoginLinkWindow = window }()
+
+	// every link is outside the window of the previous one
+	for i := 0; i <= database.LoginLinkLimit; i++ {
+		_, err := db.CreateLoginLink(a.Email, a.ID)
+		assert.Nil(t, err)
+	}
+}
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher hashes passwords into PHC strings, $<id>$<params>$<salt>$<hash>.
+// Stored hashes are compared by the hasher registered for their id, so the
+// algorithm can change without invalidating existing passwords.
+type Hasher interface {
+	// ID is the algorithm identifier hashes start with, e.g argon2id
+	ID() string
+	Hash(password string) (string, error)
+	// Compare returns ErrPasswordMismatch when hash isn't of password
+	Compare(hash, password string) error
+	// Outdated reports whether hash was made with other parameters than
+	// the hasher would use now
+	Outdated(hash string) bool
+}
+
+var (
+	// PasswordHasher hashes new passwords, bcrypt by default. PASSWORD_HASH
+	// selects argon2id instead, BCRYPT_COST, ARGON2_MEMORY (in KiB),
+	// ARGON2_TIME and ARGON2_THREADS change their parameters
+	PasswordHasher Hasher = &Bcrypt{Cost: bcrypt.DefaultCost}
+
+	hashers = map[string]Hasher{}
+)
+
+func init() {
+	RegisterHasher(&Bcrypt{Cost: bcrypt.DefaultCost})
+	RegisterHasher(DefaultArgon2id)
+}
+
+// RegisterHasher lets hashes made by h be compared, its parameters don't
+// matter as they're read from each hash
+func RegisterHasher(h Hasher) {
+	hashers[h.ID()] = h
+}
+
+// hasherFor returns the hasher able to compare hash
+func hasherFor(hash string) (Hasher, error) {
+	id := hashID(hash)
+	if id == PasswordHasher.ID() {
+		return PasswordHasher, nil
+	}
+
+	h, ok := hashers[id]
+	if !ok {
+		return nil, ErrUnknownHash
+	}
+
+	return h, nil
+}
+
+// hashID returns the algorithm of a PHC string, bcrypt's own $2a$ format is
+// treated as bcrypt
+func hashID(hash string) string {
+	if isBcryptMCF(hash) {
+		return "bcrypt"
+	}
+
+	parts := strings.SplitN(hash, "$", 3)
+	if len(parts) < 3 || parts[0] != "" {
+		return ""
+	}
+
+	return parts[1]
+}
+
+// Bcrypt hashes passwords with bcrypt as $bcrypt$r=<cost>$<salt>$<hash>,
+// hashes in bcrypt's own $2a$ format are accepted but always outdated.
+type Bcrypt struct {
+	Cost int
+}
+
+func (b *Bcrypt) ID() string {
+	return "bcrypt"
+}
+
+func (b *Bcrypt) Hash(password string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	// $2a$10$ followed by 22 characters of salt and the hash
+	parts := strings.Split(string(h), "$")
+	cost, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$bcrypt$r=%d$%s$%s", cost, parts[3][:22], parts[3][22:]), nil
+}
+
+func (b *Bcrypt) Compare(hash, password string) error {
+	mcf, _, err := bcryptMCF(hash)
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(mcf), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrPasswordMismatch
+	}
+
+	return err
+}
+
+func (b *Bcrypt) Outdated(hash string) bool {
+	_, cost, err := bcryptMCF(hash)
+	return err != nil || isBcryptMCF(hash) || cost != b.Cost
+}
+
+func isBcryptMCF(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+// bcryptMCF converts a PHC bcrypt hash back to the format the bcrypt
+// package uses, returning its cost
+func bcryptMCF(hash string) (string, int, error) {
+	if isBcryptMCF(hash) {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return hash, cost, err
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[1] != "bcrypt" {
+		return "", 0, ErrUnknownHash
+	}
+
+	var cost int
+	_, err := fmt.Sscanf(parts[2], "r=%d", &cost)
+	if err != nil {
+		return "", 0, ErrUnknownHash
+	}
+
+	return fmt.Sprintf("$2a$%02d$%s%s", cost, parts[3], parts[4]), cost, nil
+}
+
+// DefaultArgon2id are the parameters of argon2id when PASSWORD_HASH selects
+// it, as recommended by RFC 9106 for memory constrained environments
+var DefaultArgon2id = &Argon2id{Memory: 64 * 1024, Time: 3, Threads: 2}
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2id hashes passwords with argon2id as
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>. Memory is in
+// KiB.
+type Argon2id struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+func (a *Argon2id) ID() string {
+	return "argon2id"
+}
+
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.Memory, a.Time, a.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a *Argon2id) Compare(hash, password string) error {
+	p, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+func (a *Argon2id) Outdated(hash string) bool {
+	p, _, _, err := parseArgon2id(hash)
+	return err != nil || *p != *a
+}
+
+// parseArgon2id returns the parameters, salt and key of an argon2id hash
+func parseArgon2id(hash string) (*Argon2id, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	p := &Argon2id{}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads)
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	return p, salt, key, nil
+}
+
+func hasherFromEnv() error {
+	b := &Bcrypt{Cost: bcrypt.DefaultCost}
+	if n, err := strconv.Atoi(os.Getenv("BCRYPT_COST")); err == nil && n >= bcrypt.MinCost && n <= bcrypt.MaxCost {
+		b.Cost = n
+	}
+
+	a := *DefaultArgon2id
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_MEMORY"), 10, 32); err == nil && n > 0 {
+		a.Memory = uint32(n)
+	}
+
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_TIME"), 10, 32); err == nil && n > 0 {
+		a.Time = uint32(n)
+	}
+
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_THREADS"), 10, 8); err == nil && n > 0 {
+		a.Threads = uint8(n)
+	}
+
+	switch h := os.Getenv("PASSWORD_HASH"); h {
+	case "", "bcrypt":
+		PasswordHasher = b
+	case "argon2id":
+		PasswordHasher = &a
+	default:
+		return fmt.Errorf("unknown PASSWORD_HASH %q, use bcrypt or argon2id", h)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2id keeps tests quick, real hashes use DefaultArgon2id
+var fastArgon2id = &Argon2id{Memory: 1024, Time: 1, Threads: 1}
+
+func withHasher(h Hasher) func() {
+	previous := PasswordHasher
+	PasswordHasher = h
+	return func() { PasswordHasher = previous }
+}
+
+func TestBcrypt(t *testing.T) {
+	b := &Bcrypt{Cost: bcrypt.MinCost}
+
+	hash, err := b.Hash("password")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$bcrypt$r=4$"), hash)
+	assert.Len(t, strings.Split(hash, "$"), 5)
+
+	assert.Nil(t, b.Compare(hash, "password"))
+	assert.Equal(t, ErrPasswordMismatch, b.Compare(hash, "wrong"))
+	assert.False(t, b.Outdated(hash))
+	assert.True(t, (&Bcrypt{Cost: 5}).Outdated(hash))
+}
+
+func TestBcryptLegacy(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.Nil(t, err)
+
+	b := &Bcrypt{Cost: bcrypt.MinCost}
+	assert.Nil(t, b.Compare(string(legacy), "password"))
+	assert.Equal(t, ErrPasswordMismatch, b.Compare(string(legacy), "wrong"))
+
+	// same cost but not PHC, so it's rewritten at the next login
+	assert.True(t, b.Outdated(string(legacy)))
+}
+
+func TestArgon2id(t *testing.T) {
+	hash, err := fastArgon2id.Hash("password")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), hash)
+
+	assert.Nil(t, fastArgon2id.Compare(hash, "password"))
+	assert.Equal(t, ErrPasswordMismatch, fastArgon2id.Compare(hash, "wrong"))
+	assert.False(t, fastArgon2id.Outdated(hash))
+	assert.True(t, (&Argon2id{Memory: 2048, Time: 1, Threads: 1}).Outdated(hash))
+
+	// parameters come from the hash rather than the hasher
+	assert.Nil(t, DefaultArgon2id.Compare(hash, "password"))
+
+	other, err := fastArgon2id.Hash("password")
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, other)
+
+	assert.Equal(t, ErrUnknownHash, fastArgon2id.Compare("$argon2id$v=19$m=1024$salt$key", "password"))
+}
+
+func TestComparePasswordToHash(t *testing.T) {
+	defer withHasher(&Bcrypt{Cost: bcrypt.MinCost})()
+
+	a := &Account{}
+	assert.Nil(t, a.HashPassword("password"))
+	assert.False(t, a.PasswordOutdated())
+
+	// switching algorithm keeps existing hashes working, but outdated
+	PasswordHasher = fastArgon2id
+	assert.Nil(t, a.ComparePasswordToHash("password"))
+	assert.Equal(t, ErrPasswordMismatch, a.ComparePasswordToHash("wrong"))
+	assert.True(t, a.PasswordOutdated())
+
+	assert.Nil(t, a.HashPassword("password"))
+	assert.True(t, strings.HasPrefix(a.HashedPassword, "$argon2id$"))
+	assert.False(t, a.PasswordOutdated())
+	assert.Nil(t, a.ComparePasswordToHash("password"))
+
+	a.HashedPassword = "$scrypt$ln=16,r=8,p=1$salt$hash"
+	assert.Equal(t, ErrUnknownHash, a.ComparePasswordToHash("password"))
+	assert.True(t, a.PasswordOutdated())
+
+	a.HashedPassword = "plaintext"
+	assert.Equal(t, ErrUnknownHash, a.ComparePasswordToHash("password"))
+}
+
+// recordingHasher records the hashes compared by the hasher it wraps
+type recordingHasher struct {
+	Hasher
+	compared []string
+}
+
+func (r *recordingHasher) Compare(hash, password string) error {
+	r.compared = append(r.compared, hash)
+	return r.Hasher.Compare(hash, password)
+}
+
+// hashParams strips the salt and hash from a PHC string
+func hashParams(hash string) string {
+	parts := strings.Split(hash, "$")
+	return strings.Join(parts[:len(parts)-2], "$")
+}
+
+func TestCompareDummyPassword(t *testing.T) {
+	defer withHasher(PasswordHasher)()
+
+	hashers := []Hasher{
+		&Bcrypt{Cost: bcrypt.MinCost},
+		fastArgon2id,
+		&Argon2id{Memory: 2048, Time: 1, Threads: 1},
+	}
+
+	// the dummy follows every change of algorithm and parameters
+	for _, h := range hashers {
+		r := &recordingHasher{Hasher: h}
+		PasswordHasher = r
+
+		a := &Account{}
+		assert.Nil(t, a.HashPassword("password"))
+		assert.Equal(t, ErrPasswordMismatch, a.ComparePasswordToHash("wrong"))
+		CompareDummyPassword("wrong")
+
+		if assert.Len(t, r.compared, 2) {
+			assert.Equal(t, hashParams(r.compared[0]), hashParams(r.compared[1]))
+		}
+	}
+}
+
+func TestHasherFromEnv(t *testing.T) {
+	defer withHasher(PasswordHasher)()
+	for _, env := range []string{"PASSWORD_HASH", "ARGON2_MEMORY", "BCRYPT_COST"} {
+		defer os.Unsetenv(env)
+	}
+
+	os.Setenv("PASSWORD_HASH", "argon2id")
+	os.Setenv("ARGON2_MEMORY", "2048")
+	assert.Nil(t, hasherFromEnv())
+	assert.Equal(t, &Argon2id{Memory: 2048, Time: DefaultArgon2id.Time, Threads: DefaultArgon2id.Threads}, PasswordHasher)
+
+	os.Setenv("PASSWORD_HASH", "bcrypt")
+	os.Setenv("BCRYPT_COST", "12")
+	assert.Nil(t, hasherFromEnv())
+	assert.Equal(t, &Bcrypt{Cost: 12}, PasswordHasher)
+
+	os.Setenv("PASSWORD_HASH", "md5")
+	assert.NotNil(t, hasherFromEnv())
+}
@@ -0,0 +1,310 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pageTokenVersion is bumped whenever the cursor encoding changes so old
+// tokens can be recognised and rejected or converted.
+const pageTokenVersion = 1
+
+var (
+	// DefaultPageSize is used when List is called with a count of 0
+	DefaultPageSize int32 = 25
+	// MaxPageSize caps the count passed to List
+	MaxPageSize int32 = 100
+)
+
+// orderColumns are the columns List can be ordered by
+var orderColumns = map[string]bool{
+	"created_at": true,
+	"email":      true,
+	"name":       true,
+}
+
+// ListOptions filters and orders the accounts returned by List. The zero
+// value returns every account ordered by creation time.
+type ListOptions struct {
+	// EmailPrefix matches emails starting with the prefix, ignoring case
+	EmailPrefix string
+	// NameContains matches names containing the string, ignoring case
+	NameContains string
+	// CreatedAfter and CreatedBefore are exclusive bounds on created_at
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Confirmed matches confirmed accounts when true and unconfirmed
+	// accounts when false, nil matches both
+	Confirmed *bool
+	// Metadata matches accounts with all of the given key/value pairs
+	Metadata map[string]string
+	// OrderBy is a column from orderColumns optionally followed by "asc"
+	// or "desc", i.e "email desc". Ties are broken by id.
+	OrderBy string
+}
+
+func (o ListOptions) isZero() bool {
+	return o.EmailPrefix == "" && o.NameContains == "" &&
+		o.CreatedAfter.IsZero() && o.CreatedBefore.IsZero() &&
+		o.Confirmed == nil && len(o.Metadata) == 0
+}
+
+// match reports whether a passes the filters in o
+func (o ListOptions) match(a *Account) bool {
+	if o.EmailPrefix != "" && !strings.HasPrefix(strings.ToLower(a.Email), strings.ToLower(o.EmailPrefix)) {
+		return false
+	}
+
+	if o.NameContains != "" && !strings.Contains(strings.ToLower(a.Name), strings.ToLower(o.NameContains)) {
+		return false
+	}
+
+	if !o.CreatedAfter.IsZero() && !a.CreatedAt.After(o.CreatedAfter) {
+		return false
+	}
+
+	if !o.CreatedBefore.IsZero() && !a.CreatedAt.Before(o.CreatedBefore) {
+		return false
+	}
+
+	if o.Confirmed != nil && *o.Confirmed != (a.ConfirmationTokenHash == "") {
+		return false
+	}
+
+	for k, v := range o.Metadata {
+		if mv, ok := a.Metadata[k]; !ok || mv != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hash identifies the filters and ordering a page token was issued for, so
+// a token can't be reused with different options. It is blank for the
+// default options so plain cursor tokens stay valid.
+func (o ListOptions) hash(order listOrder) string {
+	if o.isZero() && order == defaultOrder {
+		return ""
+	}
+
+	b, _ := json.Marshal(struct {
+		ListOptions
+		Order listOrder
+	}{o, order})
+
+	h := fnv.New64a()
+	h.Write(b)
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// likeEscape escapes s for use in a LIKE pattern with ESCAPE '!'
+func likeEscape(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+type listOrder struct {
+	Column string
+	Desc   bool
+}
+
+var defaultOrder = listOrder{Column: "created_at"}
+
+func parseOrder(s string) (listOrder, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return defaultOrder, nil
+	}
+
+	o := listOrder{Column: fields[0]}
+	if !orderColumns[o.Column] || len(fields) > 2 {
+		return o, ErrInvalidOrderBy
+	}
+
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			o.Desc = true
+		default:
+			return o, ErrInvalidOrderBy
+		}
+	}
+
+	return o, nil
+}
+
+func (o listOrder) direction() string {
+	if o.Desc {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+// comparison is the operator selecting rows after a cursor
+func (o listOrder) comparison() string {
+	if o.Desc {
+		return "<"
+	}
+
+	return ">"
+}
+
+// key returns the value of the ordering column for a
+func (o listOrder) key(a *Account) string {
+	switch o.Column {
+	case "email":
+		return a.Email
+	case "name":
+		return a.Name
+	}
+
+	return ""
+}
+
+// less reports whether a sorts before b
+func (o listOrder) less(a, b *Account) bool {
+	var before, equal bool
+	if o.Column == "created_at" {
+		before, equal = a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+	} else {
+		before, equal = o.key(a) < o.key(b), o.key(a) == o.key(b)
+	}
+
+	if equal {
+		before = a.ID < b.ID
+	}
+
+	if o.Desc {
+		return !before && a.ID != b.ID
+	}
+
+	return before
+}
+
+// pageCursor marks the position after which the next page starts.
+type pageCursor struct {
+	Version   int       `json:"v"`
+	CreatedAt time.Time `json:"c"`
+	Key       string    `json:"k,omitempty"`
+	ID        string    `json:"i"`
+	Options   string    `json:"o,omitempty"`
+
+	// Offset is set instead when a legacy numeric page token is given,
+	// these are accepted during the transition to cursor tokens
+	Offset int `json:"-"`
+}
+
+// value returns the cursor position for the ordering column
+func (c pageCursor) value(o listOrder) interface{} {
+	if o.Column == "created_at" {
+		return c.CreatedAt
+	}
+
+	return c.Key
+}
+
+// after reports whether a sorts after the cursor position
+func (c pageCursor) after(o listOrder, a *Account) bool {
+	return o.less(&Account{ID: c.ID, CreatedAt: c.CreatedAt, Email: c.Key, Name: c.Key}, a)
+}
+
+func decodePageToken(token string, opts ListOptions, order listOrder) (pageCursor, error) {
+	var c pageCursor
+	if token == "" {
+		return c, nil
+	}
+
+	offset, err := strconv.Atoi(token)
+	if err == nil {
+		if offset < 0 {
+			return c, ErrInvalidPageToken
+		}
+
+		c.Offset = offset
+		return c, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidPageToken
+	}
+
+	err = json.Unmarshal(b, &c)
+	if err != nil || c.Version != pageTokenVersion || c.ID == "" {
+		return pageCursor{}, ErrInvalidPageToken
+	}
+
+	if c.Options != opts.hash(order) {
+		return pageCursor{}, ErrInvalidPageToken
+	}
+
+	return c, nil
+}
+
+func encodePageToken(a *Account, opts ListOptions, order listOrder) string {
+	b, _ := json.Marshal(pageCursor{
+		Version:   pageTokenVersion,
+		CreatedAt: a.CreatedAt,
+		Key:       order.key(a),
+		ID:        a.ID,
+		Options:   opts.hash(order),
+	})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// listParams validates the arguments common to every List implementation
+func listParams(count32 int32, token string, opts ListOptions) (int, listOrder, pageCursor, error) {
+	order, err := parseOrder(opts.OrderBy)
+	if err != nil {
+		return 0, order, pageCursor{}, err
+	}
+
+	cursor, err := decodePageToken(token, opts, order)
+	if err != nil {
+		return 0, order, cursor, err
+	}
+
+	return pageSize(count32), order, cursor, nil
+}
+
+// pageSize applies DefaultPageSize and MaxPageSize to the requested count
+func pageSize(count int32) int {
+	if count <= 0 {
+		count = DefaultPageSize
+	}
+
+	if count > MaxPageSize {
+		count = MaxPageSize
+	}
+
+	return int(count)
+}
+
+// nextPage takes accounts fetched with a limit of count+1 and returns the
+// page to send back along with the token for the following page, if any.
+func nextPage(accounts []*Account, count int, opts ListOptions, order listOrder) ([]*Account, string) {
+	if len(accounts) <= count {
+		return accounts, ""
+	}
+
+	accounts = accounts[:count]
+	return accounts, encodePageToken(accounts[count-1], opts, order)
+}
+
+func pageSizesFromEnv() {
+	if s, err := strconv.Atoi(os.Getenv("LIST_DEFAULT_PAGE_SIZE")); err == nil && s > 0 {
+		DefaultPageSize = int32(s)
+	}
+
+	if s, err := strconv.Atoi(os.Getenv("LIST_MAX_PAGE_SIZE")); err == nil && s > 0 {
+		MaxPageSize = int32(s)
+	}
+}
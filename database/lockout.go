@@ -0,0 +1,99 @@
+package database
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// LockoutThreshold is how many failed logins in a row lock a subject
+	// out, 0 turns lockouts off. It can be changed with LOCKOUT_THRESHOLD
+	LockoutThreshold = 5
+	// LockoutBase is how long the first lockout lasts, every failure after
+	// it doubles the lockout up to LockoutMax. They can be changed with
+	// LOCKOUT_BASE and LOCKOUT_MAX
+	LockoutBase = time.Minute
+	LockoutMax  = time.Hour
+	// LockoutWindow is how long failures are remembered for, the count starts
+	// again after a quiet period this long. It can be changed with
+	// LOCKOUT_WINDOW
+	LockoutWindow = 24 * time.Hour
+)
+
+// LoginFailures counts the failed logins in a row for a subject, either an
+// account or an email address. See AccountSubject and EmailSubject.
+type LoginFailures struct {
+	tableName struct{} `sql:"login_failures"`
+
+	Subject      string `sql:",pk"`
+	Failures     int    `sql:",notnull"`
+	LastFailedAt time.Time
+	LockedUntil  time.Time
+}
+
+// AccountSubject returns the subject failures for an account are counted as
+func AccountSubject(ID string) string {
+	return "account:" + ID
+}
+
+// EmailSubject returns the subject failures for an email are counted as,
+// including emails without an account
+func EmailSubject(email string) string {
+	return "email:" + strings.ToLower(email)
+}
+
+// Locked reports whether logins for the subject are refused, and for how
+// much longer
+func (f *LoginFailures) Locked() (bool, time.Duration) {
+	d := time.Until(f.LockedUntil)
+	return d > 0, d
+}
+
+// fail counts another failure at now, locking the subject once there have
+// been LockoutThreshold of them
+func (f *LoginFailures) fail(now time.Time) {
+	if now.Sub(f.LastFailedAt) > LockoutWindow {
+		f.Failures = 0
+	}
+
+	f.Failures++
+	f.LastFailedAt = now
+
+	if LockoutThreshold > 0 && f.Failures >= LockoutThreshold {
+		f.LockedUntil = now.Add(lockoutDuration(f.Failures - LockoutThreshold))
+	}
+}
+
+// lockoutDuration returns LockoutBase doubled n times, capped at LockoutMax
+func lockoutDuration(n int) time.Duration {
+	d := LockoutBase
+	for i := 0; i < n && d < LockoutMax; i++ {
+		d *= 2
+	}
+
+	if d > LockoutMax {
+		d = LockoutMax
+	}
+
+	return d
+}
+
+func lockoutFromEnv() {
+	if n, err := strconv.Atoi(os.Getenv("LOCKOUT_THRESHOLD")); err == nil && n >= 0 {
+		LockoutThreshold = n
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("LOCKOUT_BASE")); err == nil && d > 0 {
+		LockoutBase = d
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("LOCKOUT_MAX")); err == nil && d > 0 {
+		LockoutMax = d
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("LOCKOUT_WINDOW")); err == nil && d > 0 {
+		LockoutWindow = d
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// LoginLinkTTL is how long a login link can be used for, it can be
+	// changed with LOGIN_LINK_TTL
+	LoginLinkTTL = 15 * time.Minute
+	// LoginLinkLimit is how many login links can be requested for an email
+	// within LoginLinkWindow, 0 turns the limit off. They can be changed
+	// with LOGIN_LINK_LIMIT and LOGIN_LINK_WINDOW
+	LoginLinkLimit  = 3
+	LoginLinkWindow = time.Hour
+)
+
+// LoginLink is a single use token that logs in to an account without its
+// password. Only the digest of the token is stored, the token itself is set
+// on the link returned by CreateLoginLink. Links requested for an email
+// without an account have no AccountID, they can't be used but count
+// towards the limit like any other.
+type LoginLink struct {
+	tableName struct{} `sql:"login_links"`
+
+	Token     string `sql:"-"`
+	TokenHash string `sql:"token,pk"`
+	Email     string
+	AccountID string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// RateLimitError is returned when too many requests have been made, they
+// can be made again after RetryAfter
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+}
+
+// newLoginLink returns a link for email with a new token, created now
+func newLoginLink(email, accountID string, now time.Time) (*LoginLink, error) {
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginLink{
+		Token:     t,
+		TokenHash: HashToken(t),
+		Email:     strings.ToLower(email),
+		AccountID: accountID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(LoginLinkTTL),
+	}, nil
+}
+
+// expired reports whether the link can no longer be used
+func (l *LoginLink) expired() bool {
+	return !l.ExpiresAt.After(time.Now())
+}
+
+// loginLinkWindowStart returns when the links counted towards the limit at
+// now were created after
+func loginLinkWindowStart(now time.Time) time.Time {
+	return now.Add(-LoginLinkWindow)
+}
+
+// loginLinkLimited returns a *RateLimitError when another link can't be
+// requested at now, given when the links in the window were created oldest
+// first
+func loginLinkLimited(created []time.Time, now time.Time) error {
+	if LoginLinkLimit <= 0 || len(created) < LoginLinkLimit {
+		return nil
+	}
+
+	// another can be requested once enough have left the window
+	oldest := created[len(created)-LoginLinkLimit]
+	return &RateLimitError{RetryAfter: oldest.Add(LoginLinkWindow).Sub(now)}
+}
+
+func loginLinksFromEnv() {
+	if d, err := time.ParseDuration(os.Getenv("LOGIN_LINK_TTL")); err == nil && d > 0 {
+		LoginLinkTTL = d
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("LOGIN_LINK_LIMIT")); err == nil && n >= 0 {
+		LoginLinkLimit = n
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("LOGIN_LINK_WINDOW")); err == nil && d > 0 {
+		LoginLinkWindow = d
+	}
+}
@@ -0,0 +1,897 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lileio/image_service"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Memory is a thread-safe, in-memory implementation of Database. It mirrors
+// the behaviour of the PostgreSQL driver and is intended for tests and local
+// development, nothing is persisted between runs.
+type Memory struct {
+	mu       sync.RWMutex
+	accounts map[string]*Account
+	sessions map[string]*Session
+	mfa      map[string]*Mfa
+	failures map[string]*LoginFailures
+	links    map[string]*LoginLink
+}
+
+var _ Database = (*Memory)(nil)
+
+func (m *Memory) Migrate() error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) Truncate() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts = map[string]*Account{}
+	m.sessions = map[string]*Session{}
+	m.mfa = map[string]*Mfa{}
+	m.failures = map[string]*LoginFailures{}
+	m.links = map[string]*LoginLink{}
+	return nil
+}
+
+func (m *Memory) List(count32 int32, token string, opts ListOptions) (accounts []*Account, next_token string, err error) {
+	count, order, cursor, err := listParams(count32, token, opts)
+	if err != nil {
+		return accounts, next_token, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	all := make([]*Account, 0, len(m.accounts))
+	for _, a := range m.accounts {
+		if opts.match(a) && (cursor.ID == "" || cursor.after(order, a)) {
+			all = append(all, a)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return order.less(all[i], all[j])
+	})
+
+	for i := cursor.Offset; i < len(all) && len(accounts) <= count; i++ {
+		accounts = append(accounts, copyAccount(all[i]))
+	}
+
+	accounts, next_token = nextPage(accounts, count, opts, order)
+	return accounts, next_token, nil
+}
+
+func (m *Memory) ReadByID(ID string) (*Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, ok := m.accounts[ID]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	return copyAccount(a), nil
+}
+
+func (m *Memory) ReadByEmail(email string) (*Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a := m.findByEmail(email)
+	if a == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	return copyAccount(a), nil
+}
+
+func (m *Memory) Create(a *Account, password string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.accounts == nil {
+		m.accounts = map[string]*Account{}
+	}
+
+	if m.emailTaken(a.Email, "") {
+		return ErrEmailExists
+	}
+
+	if a.ConfirmationToken == "" {
+		t, err := m.uniqueToken(func(a *Account) string { return a.ConfirmationTokenHash })
+		if err != nil {
+			logrus.Errorf("confirm token generation error %v", err)
+			return err
+		}
+
+		a.ConfirmationToken = t
+	}
+
+	a.hashTokens()
+	a.ID = uuid.NewV1().String()
+	a.CreatedAt = time.Now().UTC()
+	a.UpdatedAt = a.CreatedAt
+	a.Version = 1
+
+	m.accounts[a.ID] = storedAccount(a)
+	return nil
+}
+
+func (m *Memory) Update(a *Account, fields ...string) error {
+	fields, err := updateFields(fields)
+	if err != nil {
+		return err
+	}
+
+	err = a.ValidFields(fields)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca, ok := m.accounts[a.ID]
+	if !ok {
+		return ErrAccountNotFound
+	}
+
+	if a.Version != 0 && a.Version != ca.Version {
+		return ErrVersionConflict
+	}
+
+	for _, f := range fields {
+		if f == "email" && m.emailTaken(a.Email, a.ID) {
+			return ErrEmailExists
+		}
+	}
+
+	c := copyAccount(a)
+	for _, f := range fields {
+		switch f {
+		case "name":
+			ca.Name = c.Name
+		case "email":
+			ca.Email = c.Email
+		case "images":
+			ca.Images = c.Images
+		case "metadata":
+			ca.Metadata = c.Metadata
+		case "hashed_password":
+			ca.HashedPassword = c.HashedPassword
+			ca.PepperID = c.PepperID
+		}
+	}
+
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+
+	*a = *copyAccount(ca)
+	return nil
+}
+
+func (m *Memory) GeneratePasswordToken(email string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca := m.findByEmail(email)
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	t, err := m.uniqueToken(func(a *Account) string { return a.PasswordResetTokenHash })
+	if err != nil {
+		logrus.Errorf("password token generation error %v", err)
+		return nil, err
+	}
+
+	ca.PasswordResetTokenHash = HashToken(t)
+	ca.PasswordResetExpiresAt = passwordResetExpiry()
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+
+	a := copyAccount(ca)
+	a.PasswordResetToken = t
+	return a, nil
+}
+
+func (m *Memory) ReadByPasswordToken(token string) (*Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ca := m.findByToken(token, func(a *Account) string { return a.PasswordResetTokenHash })
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	return copyAccount(ca), nil
+}
+
+func (m *Memory) UpdatePassword(token, hashed_password, pepperID string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca := m.findByToken(token, func(a *Account) string { return a.PasswordResetTokenHash })
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if ca.passwordResetExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	ca.HashedPassword = hashed_password
+	ca.PepperID = pepperID
+	ca.PasswordResetTokenHash = ""
+	ca.PasswordResetExpiresAt = time.Time{}
+	ca.cancelEmailChange()
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+	return copyAccount(ca), nil
+}
+
+func (m *Memory) RequestEmailChange(ID, email string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca, ok := m.accounts[ID]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	if m.emailTaken(email, ID) {
+		return nil, ErrEmailExists
+	}
+
+	t, err := m.uniqueToken(func(a *Account) string { return a.EmailChangeTokenHash })
+	if err != nil {
+		logrus.Errorf("email change token generation error %v", err)
+		return nil, err
+	}
+
+	ca.PendingEmail = email
+	ca.EmailChangeTokenHash = HashToken(t)
+	ca.EmailChangeExpiresAt = emailChangeExpiry()
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+
+	a := copyAccount(ca)
+	a.EmailChangeToken = t
+	return a, nil
+}
+
+func (m *Memory) ConfirmEmailChange(token string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca := m.findByToken(token, func(a *Account) string { return a.EmailChangeTokenHash })
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if ca.emailChangeExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// the email may have been taken since the change was requested
+	if m.emailTaken(ca.PendingEmail, ca.ID) {
+		return nil, ErrEmailExists
+	}
+
+	previous := ca.Email
+	ca.Email = ca.PendingEmail
+	ca.cancelEmailChange()
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+
+	a := copyAccount(ca)
+	a.PreviousEmail = previous
+	return a, nil
+}
+
+func (m *Memory) Confirm(token string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca := m.findByToken(token, func(a *Account) string { return a.ConfirmationTokenHash })
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if ca.confirmationExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	ca.ConfirmationTokenHash = ""
+	ca.ConfirmationExpiresAt = time.Time{}
+	ca.ConfirmedAt = time.Now().UTC()
+	ca.UpdatedAt = ca.ConfirmedAt
+	ca.Version++
+	return copyAccount(ca), nil
+}
+
+func (m *Memory) GenerateConfirmationToken(email string) (*Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca := m.findByEmail(email)
+	if ca == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if ca.Confirmed() {
+		return nil, ErrConfirmed
+	}
+
+	t, err := m.uniqueToken(func(a *Account) string { return a.ConfirmationTokenHash })
+	if err != nil {
+		logrus.Errorf("confirm token generation error %v", err)
+		return nil, err
+	}
+
+	ca.ConfirmationTokenHash = HashToken(t)
+	ca.ConfirmationExpiresAt = confirmationExpiry()
+	ca.UpdatedAt = time.Now().UTC()
+	ca.Version++
+
+	a := copyAccount(ca)
+	a.ConfirmationToken = t
+	return a, nil
+}
+
+func (m *Memory) RecordLogin(a *Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ca, ok := m.accounts[a.ID]
+	if !ok {
+		return ErrAccountNotFound
+	}
+
+	ca.LastLoginAt = time.Now().UTC()
+	a.LastLoginAt = ca.LastLoginAt
+	return nil
+}
+
+func (m *Memory) Delete(ID string, version int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if version != 0 {
+		ca, ok := m.accounts[ID]
+		if !ok {
+			return ErrAccountNotFound
+		}
+
+		if ca.Version != version {
+			return ErrVersionConflict
+		}
+	}
+
+	delete(m.accounts, ID)
+	delete(m.mfa, ID)
+	m.revokeAllSessions(ID)
+	return nil
+}
+
+func (m *Memory) CreateSession(s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.sessions == nil {
+		m.sessions = map[string]*Session{}
+	}
+
+	err := s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return err
+	}
+
+	s.ID = uuid.NewV1().String()
+	s.CreatedAt = s.LastSeenAt
+
+	m.sessions[s.ID] = storedSession(s)
+	return nil
+}
+
+func (m *Memory) RefreshSession(refreshToken string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if refreshToken == "" {
+		return nil, ErrSessionNotFound
+	}
+
+	var cs *Session
+	digest := HashToken(refreshToken)
+	for _, s := range m.sessions {
+		if s.RefreshTokenHash == digest {
+			cs = s
+			break
+		}
+	}
+
+	if cs == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if cs.refreshExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	s := *cs
+	err := s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return nil, err
+	}
+
+	*cs = *storedSession(&s)
+	return &s, nil
+}
+
+func (m *Memory) TouchSession(accessToken string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if accessToken == "" {
+		return nil, ErrSessionNotFound
+	}
+
+	digest := HashToken(accessToken)
+	for _, s := range m.sessions {
+		if s.AccessTokenHash != digest {
+			continue
+		}
+
+		if s.accessExpired() {
+			return nil, ErrTokenExpired
+		}
+
+		s.LastSeenAt = time.Now().UTC().Truncate(time.Microsecond)
+		cs := *s
+		return &cs, nil
+	}
+
+	return nil, ErrSessionNotFound
+}
+
+func (m *Memory) ListSessions(accountID string) ([]*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := []*Session{}
+	for _, s := range m.sessions {
+		if s.AccountID == accountID && !s.refreshExpired() {
+			c := *s
+			sessions = append(sessions, &c)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].ID < sessions[j].ID
+		}
+
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (m *Memory) RevokeSession(ID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[ID]; !ok {
+		return ErrSessionNotFound
+	}
+
+	delete(m.sessions, ID)
+	return nil
+}
+
+func (m *Memory) RevokeAllSessions(accountID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revokeAllSessions(accountID)
+	return nil
+}
+
+// revokeAllSessions deletes every session of an account. Callers must hold
+// the lock.
+func (m *Memory) revokeAllSessions(accountID string) {
+	for id, s := range m.sessions {
+		if s.AccountID == accountID {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+func (m *Memory) ReadMfa(accountID string) (*Mfa, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return nil, ErrMfaNotFound
+	}
+
+	return copyMfa(mfa), nil
+}
+
+func (m *Memory) EnrolMfa(accountID, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.mfa == nil {
+		m.mfa = map[string]*Mfa{}
+	}
+
+	if mfa, ok := m.mfa[accountID]; ok && mfa.Enabled {
+		return ErrMfaEnabled
+	}
+
+	m.mfa[accountID] = &Mfa{
+		AccountID: accountID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+func (m *Memory) EnableMfa(accountID string, step int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return ErrMfaNotFound
+	}
+
+	if mfa.Enabled {
+		return ErrMfaEnabled
+	}
+
+	mfa.Enabled = true
+	mfa.LastUsedStep = step
+	return nil
+}
+
+func (m *Memory) DisableMfa(accountID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.mfa[accountID]; !ok {
+		return ErrMfaNotFound
+	}
+
+	delete(m.mfa, accountID)
+	return nil
+}
+
+func (m *Memory) SetRecoveryCodes(accountID string, hashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return ErrMfaNotFound
+	}
+
+	mfa.RecoveryCodeHashes = append([]string{}, hashes...)
+	return nil
+}
+
+func (m *Memory) UseMfaStep(accountID string, step int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return ErrMfaNotFound
+	}
+
+	if step <= mfa.LastUsedStep {
+		return ErrMfaCodeUsed
+	}
+
+	mfa.LastUsedStep = step
+	return nil
+}
+
+func (m *Memory) UseRecoveryCode(accountID, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return ErrMfaNotFound
+	}
+
+	hashes, ok := mfa.withoutRecoveryCode(hash)
+	if !ok {
+		return ErrMfaCodeUsed
+	}
+
+	mfa.RecoveryCodeHashes = hashes
+	return nil
+}
+
+func (m *Memory) CreateMfaChallenge(accountID string) (*Mfa, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mfa, ok := m.mfa[accountID]
+	if !ok {
+		return nil, ErrMfaNotFound
+	}
+
+	c := copyMfa(mfa)
+	err := c.issueChallenge()
+	if err != nil {
+		logrus.Errorf("mfa challenge generation error %v", err)
+		return nil, err
+	}
+
+	mfa.ChallengeHash = c.ChallengeHash
+	mfa.ChallengeExpiresAt = c.ChallengeExpiresAt
+	return c, nil
+}
+
+func (m *Memory) ConsumeMfaChallenge(token string) (*Mfa, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if token == "" {
+		return nil, ErrNoMfaChallenge
+	}
+
+	digest := HashToken(token)
+	for _, mfa := range m.mfa {
+		if mfa.ChallengeHash != digest {
+			continue
+		}
+
+		c := copyMfa(mfa)
+		mfa.ChallengeHash = ""
+		mfa.ChallengeExpiresAt = time.Time{}
+
+		if c.challengeExpired() {
+			return nil, ErrTokenExpired
+		}
+
+		return c, nil
+	}
+
+	return nil, ErrNoMfaChallenge
+}
+
+func (m *Memory) ReadLoginFailures(subject string) (*LoginFailures, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.failures[subject]
+	if !ok {
+		return &LoginFailures{Subject: subject}, nil
+	}
+
+	c := *f
+	return &c, nil
+}
+
+func (m *Memory) RecordLoginFailure(subject string) (*LoginFailures, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failures == nil {
+		m.failures = map[string]*LoginFailures{}
+	}
+
+	f, ok := m.failures[subject]
+	if !ok {
+		f = &LoginFailures{Subject: subject}
+		m.failures[subject] = f
+	}
+
+	f.fail(time.Now().UTC())
+
+	c := *f
+	return &c, nil
+}
+
+func (m *Memory) ResetLoginFailures(subjects ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range subjects {
+		delete(m.failures, s)
+	}
+
+	return nil
+}
+
+func (m *Memory) CreateLoginLink(email, accountID string) (*LoginLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.links == nil {
+		m.links = map[string]*LoginLink{}
+	}
+
+	now := time.Now().UTC()
+	l, err := newLoginLink(email, accountID, now)
+	if err != nil {
+		logrus.Errorf("login link token generation error %v", err)
+		return nil, err
+	}
+
+	start := loginLinkWindowStart(now)
+	created := []time.Time{}
+	for digest, other := range m.links {
+		if other.Email != l.Email {
+			continue
+		}
+
+		if other.CreatedAt.After(start) {
+			created = append(created, other.CreatedAt)
+		} else if other.expired() {
+			delete(m.links, digest)
+		}
+	}
+
+	sort.Slice(created, func(i, j int) bool { return created[i].Before(created[j]) })
+	err = loginLinkLimited(created, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the digest is kept, as it is by the other drivers
+	stored := *l
+	stored.Token = ""
+	m.links[l.TokenHash] = &stored
+	return l, nil
+}
+
+func (m *Memory) ConsumeLoginLink(token string) (*LoginLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.links[HashToken(token)]
+	if !ok || token == "" || l.AccountID == "" || !l.UsedAt.IsZero() {
+		return nil, ErrNoLoginLink
+	}
+
+	l.UsedAt = time.Now().UTC()
+
+	if l.expired() {
+		return nil, ErrTokenExpired
+	}
+
+	c := *l
+	return &c, nil
+}
+
+// emailTaken reports whether another account already uses email, compared
+// case-insensitively in the same way as the accounts_email index.
+// Callers must hold the lock.
+func (m *Memory) emailTaken(email, ignoreID string) bool {
+	for id, a := range m.accounts {
+		if id != ignoreID && strings.EqualFold(a.Email, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findByEmail returns the stored account with email ignoring case, like the
+// other drivers do. Callers must hold the lock.
+func (m *Memory) findByEmail(email string) *Account {
+	for _, a := range m.accounts {
+		if strings.EqualFold(a.Email, email) {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// findByToken returns the stored account whose token digest field matches
+// token, blank tokens never match. Callers must hold the lock.
+func (m *Memory) findByToken(token string, field func(*Account) string) *Account {
+	if token == "" {
+		return nil
+	}
+
+	digest := HashToken(token)
+	for _, a := range m.accounts {
+		if field(a) == digest {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// uniqueToken generates a random token whose digest isn't yet used by any
+// account for the given field. Callers must hold the lock.
+func (m *Memory) uniqueToken(field func(*Account) string) (string, error) {
+	for {
+		t, err := GenerateRandomString(TOKEN_LENGTH)
+		if err != nil {
+			return "", err
+		}
+
+		if m.findByToken(t, field) == nil {
+			return t, nil
+		}
+	}
+}
+
+// storedAccount copies a without its raw tokens, only their digests are kept
+func storedAccount(a *Account) *Account {
+	c := copyAccount(a)
+	c.ConfirmationToken = ""
+	c.PasswordResetToken = ""
+	return c
+}
+
+// storedSession copies s without its raw tokens
+func storedSession(s *Session) *Session {
+	c := *s
+	c.AccessToken = ""
+	c.RefreshToken = ""
+	return &c
+}
+
+func copyMfa(mfa *Mfa) *Mfa {
+	c := *mfa
+	if mfa.RecoveryCodeHashes != nil {
+		c.RecoveryCodeHashes = append([]string{}, mfa.RecoveryCodeHashes...)
+	}
+
+	return &c
+}
+
+func copyAccount(a *Account) *Account {
+	c := *a
+	c.Images = copyImages(a.Images)
+
+	if a.Metadata != nil {
+		c.Metadata = make(map[string]string, len(a.Metadata))
+		for k, v := range a.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+
+	return &c
+}
+
+func copyImages(imgs []*image_service.Image) []*image_service.Image {
+	if imgs == nil {
+		return nil
+	}
+
+	c := make([]*image_service.Image, len(imgs))
+	for i, img := range imgs {
+		ci := *img
+		c[i] = &ci
+	}
+
+	return c
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryReturnsCopies(t *testing.T) {
+	m := &Memory{}
+
+	a := Account{Name: "Alex", Email: "alex@localhost", Metadata: map[string]string{"k": "v"}}
+	err := m.Create(&a, "password")
+	assert.Nil(t, err)
+
+	a.Metadata["k"] = "changed"
+
+	ra, err := m.ReadByID(a.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "v", ra.Metadata["k"])
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"os"
+	"time"
+)
+
+// MfaChallengeTTL is how long the challenge returned when logging in to an
+// account with MFA enabled can be used for, it can be changed with
+// MFA_CHALLENGE_TTL
+var MfaChallengeTTL = 5 * time.Minute
+
+// Mfa is the TOTP enrolment of an account. The secret has to be stored as
+// is to generate codes, recovery codes and challenges are only stored as
+// digests.
+type Mfa struct {
+	tableName struct{} `sql:"mfa"`
+
+	AccountID          string `sql:",pk"`
+	Secret             string
+	Enabled            bool     `sql:",notnull"`
+	RecoveryCodeHashes []string `sql:"recovery_codes"`
+	// LastUsedStep is the time step of the last code used, codes for it or
+	// earlier steps are refused so each code only works once
+	LastUsedStep int64 `sql:",notnull"`
+	// Challenge is only set on the enrolment returned by CreateMfaChallenge
+	Challenge          string    `sql:"-"`
+	ChallengeHash      string    `sql:"challenge"`
+	ChallengeExpiresAt time.Time `db:"challenge_expires_at"`
+	CreatedAt          time.Time `db:"created_at"`
+}
+
+// issueChallenge generates a new login challenge
+func (m *Mfa) issueChallenge() error {
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		return err
+	}
+
+	m.Challenge = t
+	m.ChallengeHash = HashToken(t)
+	m.ChallengeExpiresAt = time.Now().UTC().Add(MfaChallengeTTL).Truncate(time.Microsecond)
+	return nil
+}
+
+// challengeExpired reports whether the challenge can no longer be used
+func (m *Mfa) challengeExpired() bool {
+	return !m.ChallengeExpiresAt.After(time.Now())
+}
+
+// withoutRecoveryCode returns the recovery code digests without hash, ok is
+// false if it isn't one of them
+func (m *Mfa) withoutRecoveryCode(hash string) (hashes []string, ok bool) {
+	hashes = []string{}
+	for _, h := range m.RecoveryCodeHashes {
+		if h == hash && hash != "" && !ok {
+			ok = true
+			continue
+		}
+
+		hashes = append(hashes, h)
+	}
+
+	return hashes, ok
+}
+
+func mfaTTLFromEnv() {
+	if d, err := time.ParseDuration(os.Getenv("MFA_CHALLENGE_TTL")); err == nil && d > 0 {
+		MfaChallengeTTL = d
+	}
+}
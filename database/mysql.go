@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/gemnasium/migrate/driver/mysql"
+	"github.com/gemnasium/migrate/migrate"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntry is the MySQL/MariaDB error number for a unique key
+// violation (ER_DUP_ENTRY)
+const mysqlDuplicateEntry = 1062
+
+type MySQL struct {
+	sqlDB
+	conn string
+}
+
+var _ Database = (*MySQL)(nil)
+
+func (m *MySQL) Connect(conn string) error {
+	m.conn = conn
+	cfg, err := mysql.ParseDSN(conn)
+	if err != nil {
+		return err
+	}
+
+	// created_at is scanned into a time.Time and Update relies on matched
+	// rather than changed rows to detect missing accounts
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.ClientFoundRows = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return err
+	}
+
+	// the collation of accounts.email already ignores case, so matching
+	// emails can use its index
+	m.sqlDB = sqlDB{
+		db:               db,
+		metadataExpr:     "JSON_UNQUOTE(JSON_EXTRACT(metadata, ?))",
+		emailWhere:       "email = ?",
+		uniqueEmailError: mysqlUniqueEmailError,
+		forUpdate:        " FOR UPDATE",
+	}
+	return nil
+}
+
+func (m *MySQL) Migrate() error {
+	wd := os.ExpandEnv("$GOPATH/src/github.com/lileio/account_service")
+	allErrors, ok := migrate.UpSync("mysql://"+m.conn, wd+"/migrations/mysql")
+	if !ok {
+		fmt.Printf("migration failed: %+v\n", allErrors)
+		return errors.New("migration error")
+	}
+
+	return nil
+}
+
+func (m *MySQL) Truncate() error {
+	m.db.Exec("TRUNCATE accounts;")
+	m.db.Exec("TRUNCATE sessions;")
+	m.db.Exec("TRUNCATE mfa;")
+	m.db.Exec("TRUNCATE login_failures;")
+	m.db.Exec("TRUNCATE login_links;")
+	return nil
+}
+
+// mysqlUniqueEmailError reports whether err violates the accounts_email key
+// rather than another key with email in its name, MySQL 8 prefixes the key
+// name with the table
+func mysqlUniqueEmailError(err error) bool {
+	me, ok := err.(*mysql.MySQLError)
+	return ok && me.Number == mysqlDuplicateEntry &&
+		(strings.HasSuffix(me.Message, "for key 'accounts_email'") ||
+			strings.HasSuffix(me.Message, "for key 'accounts.accounts_email'"))
+}
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLUniqueEmailError(t *testing.T) {
+	duplicate := func(key string) error {
+		return &mysql.MySQLError{
+			Number:  mysqlDuplicateEntry,
+			Message: "Duplicate entry 'x' for key '" + key + "'",
+		}
+	}
+
+	assert.True(t, mysqlUniqueEmailError(duplicate("accounts_email")))
+	assert.True(t, mysqlUniqueEmailError(duplicate("accounts.accounts_email")))
+	assert.False(t, mysqlUniqueEmailError(duplicate("accounts_email_change_token")))
+	assert.False(t, mysqlUniqueEmailError(duplicate("accounts.accounts_email_change_token")))
+}
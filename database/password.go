@@ -0,0 +1,208 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lileio/account_service/blocklist"
+)
+
+// bcryptMaxBytes is the length bcrypt truncates passwords to, anything
+// after it is ignored when comparing
+const bcryptMaxBytes = 72
+
+// minPersonalLength is the shortest part of an email or name a password
+// isn't allowed to contain, shorter ones match too many passwords by chance
+const minPersonalLength = 3
+
+// Passwords is the policy new passwords have to satisfy. It's read from the
+// JSON file at PASSWORD_POLICY_FILE if set, PASSWORD_MIN_LENGTH,
+// PASSWORD_REQUIRE_DIGIT and the like override single rules.
+var Passwords = PasswordPolicy{
+	MinLength:        8,
+	MaxLength:        bcryptMaxBytes,
+	DisallowPersonal: true,
+}
+
+// PasswordPolicy are the rules passwords are checked against. MinLength
+// counts characters while MaxLength counts bytes, as that's what bcrypt
+// limits, it can't be more than 72.
+type PasswordPolicy struct {
+	MinLength int `json:"min_length"`
+	MaxLength int `json:"max_length"`
+
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+
+	// DisallowPersonal rejects passwords containing the account's email,
+	// the part of it before the @ or any word of its name
+	DisallowPersonal bool `json:"disallow_personal"`
+
+	// Blocklist rejects known breached or common passwords, it's opened
+	// from the index at PASSWORD_BLOCKLIST
+	Blocklist *blocklist.Blocklist `json:"-"`
+}
+
+// PasswordPolicyError lists every rule a password broke
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+// Check returns a *PasswordPolicyError if password breaks any of the rules,
+// a is the account it's for and only needs the name and email set
+func (p PasswordPolicy) Check(password string, a *Account) error {
+	v := []string{}
+
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		v = append(v, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+
+	if len(password) > p.maxLength() {
+		v = append(v, fmt.Sprintf("must be at most %d bytes", p.maxLength()))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUpper && !upper {
+		v = append(v, "must contain an upper case letter")
+	}
+
+	if p.RequireLower && !lower {
+		v = append(v, "must contain a lower case letter")
+	}
+
+	if p.RequireDigit && !digit {
+		v = append(v, "must contain a digit")
+	}
+
+	if p.RequireSymbol && !symbol {
+		v = append(v, "must contain a symbol")
+	}
+
+	if p.DisallowPersonal && containsPersonal(password, a) {
+		v = append(v, "must not contain your email or name")
+	}
+
+	if p.Blocklist != nil {
+		blocked, err := p.Blocklist.Contains(password)
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			v = append(v, "is too common, it has appeared in a data breach")
+		}
+	}
+
+	if len(v) > 0 {
+		return &PasswordPolicyError{Violations: v}
+	}
+
+	return nil
+}
+
+// maxLength is MaxLength capped at what bcrypt can hash
+func (p PasswordPolicy) maxLength() int {
+	if p.MaxLength <= 0 || p.MaxLength > bcryptMaxBytes {
+		return bcryptMaxBytes
+	}
+
+	return p.MaxLength
+}
+
+// containsPersonal reports whether password contains the email or name of
+// a, ignoring case
+func containsPersonal(password string, a *Account) bool {
+	if a == nil {
+		return false
+	}
+
+	password = strings.ToLower(password)
+	email := strings.ToLower(a.Email)
+	parts := append([]string{email}, strings.Fields(strings.ToLower(a.Name))...)
+	if i := strings.Index(email, "@"); i > 0 {
+		parts = append(parts, email[:i])
+	}
+
+	for _, p := range parts {
+		if utf8.RuneCountInString(p) >= minPersonalLength && strings.Contains(password, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func passwordPolicyFromEnv() error {
+	if path := os.Getenv("PASSWORD_POLICY_FILE"); path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(b, &Passwords)
+		if err != nil {
+			return fmt.Errorf("password policy %s: %v", path, err)
+		}
+	}
+
+	if path := os.Getenv("PASSWORD_BLOCKLIST"); path != "" {
+		b, err := blocklist.Open(path)
+		if err != nil {
+			return fmt.Errorf("password blocklist %s: %v", path, err)
+		}
+
+		Passwords.Blocklist = b
+	}
+
+	ints := map[string]*int{
+		"PASSWORD_MIN_LENGTH": &Passwords.MinLength,
+		"PASSWORD_MAX_LENGTH": &Passwords.MaxLength,
+	}
+
+	for env, rule := range ints {
+		if n, err := strconv.Atoi(os.Getenv(env)); err == nil && n >= 0 {
+			*rule = n
+		}
+	}
+
+	bools := map[string]*bool{
+		"PASSWORD_REQUIRE_UPPER":     &Passwords.RequireUpper,
+		"PASSWORD_REQUIRE_LOWER":     &Passwords.RequireLower,
+		"PASSWORD_REQUIRE_DIGIT":     &Passwords.RequireDigit,
+		"PASSWORD_REQUIRE_SYMBOL":    &Passwords.RequireSymbol,
+		"PASSWORD_DISALLOW_PERSONAL": &Passwords.DisallowPersonal,
+	}
+
+	for env, rule := range bools {
+		if b, err := strconv.ParseBool(os.Getenv(env)); err == nil {
+			*rule = b
+		}
+	}
+
+	return nil
+}
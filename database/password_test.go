@@ -0,0 +1,106 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lileio/account_service/blocklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func violations(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	return err.(*PasswordPolicyError).Violations
+}
+
+func TestPasswordPolicyLength(t *testing.T) {
+	p := PasswordPolicy{MinLength: 8, MaxLength: 100}
+	a := &Account{Name: "Alex B", Email: "alexb@localhost"}
+
+	assert.Nil(t, p.Check("password", a))
+	assert.Len(t, violations(p.Check("short", a)), 1)
+
+	// characters not bytes count towards the minimum
+	assert.NotNil(t, p.Check("pässwör", a))
+
+	// bcrypt ignores anything past 72 bytes, so that's the most allowed
+	assert.Nil(t, p.Check(strings.Repeat("a", 72), a))
+	assert.Equal(t, []string{"must be at most 72 bytes"}, violations(p.Check(strings.Repeat("a", 73), a)))
+}
+
+func TestPasswordPolicyClasses(t *testing.T) {
+	p := PasswordPolicy{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	assert.Nil(t, p.Check("Passw0rd!", nil))
+	assert.Len(t, violations(p.Check("password", nil)), 3)
+	assert.Equal(t, []string{"must contain a symbol"}, violations(p.Check("Passw0rd", nil)))
+	assert.Equal(t, []string{"must contain an upper case letter"}, violations(p.Check("ünïcode 1", nil)))
+}
+
+func TestPasswordPolicyPersonal(t *testing.T) {
+	p := PasswordPolicy{DisallowPersonal: true}
+	a := &Account{Name: "Alex B", Email: "someone@example.com"}
+
+	assert.Nil(t, p.Check("correct horse", a))
+	assert.NotNil(t, p.Check("ALEX1234", a))
+	assert.NotNil(t, p.Check("someone99", a))
+	assert.NotNil(t, p.Check("xsomeone@example.comx", a))
+
+	// single letters of a name are too common to reject
+	assert.Nil(t, p.Check("bbbbbbbb", a))
+
+	p.DisallowPersonal = false
+	assert.Nil(t, p.Check("ALEX1234", a))
+}
+
+func TestPasswordPolicyBlocklist(t *testing.T) {
+	f, err := ioutil.TempFile("", "blocklist")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = blocklist.Build(f, strings.NewReader("password\nletmein123\n"), false)
+	assert.Nil(t, err)
+	f.Close()
+
+	b, err := blocklist.Open(f.Name())
+	assert.Nil(t, err)
+	defer b.Close()
+
+	p := PasswordPolicy{MinLength: 8, Blocklist: b}
+	assert.Nil(t, p.Check("correct horse", nil))
+	assert.Equal(t, []string{"is too common, it has appeared in a data breach"}, violations(p.Check("letmein123", nil)))
+	assert.Len(t, violations(p.Check("password", nil)), 1)
+}
+
+func TestPasswordPolicyFromEnv(t *testing.T) {
+	policy := Passwords
+	defer func() { Passwords = policy }()
+
+	f, err := ioutil.TempFile("", "policy")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(`{"min_length": 12, "require_digit": true}`)
+	assert.Nil(t, err)
+	f.Close()
+
+	os.Setenv("PASSWORD_POLICY_FILE", f.Name())
+	os.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+	defer os.Unsetenv("PASSWORD_POLICY_FILE")
+	defer os.Unsetenv("PASSWORD_REQUIRE_SYMBOL")
+
+	assert.Nil(t, passwordPolicyFromEnv())
+	assert.Equal(t, 12, Passwords.MinLength)
+	assert.Equal(t, bcryptMaxBytes, Passwords.MaxLength)
+	assert.True(t, Passwords.RequireDigit)
+	assert.True(t, Passwords.RequireSymbol)
+	assert.True(t, Passwords.DisallowPersonal)
+
+	os.Setenv("PASSWORD_POLICY_FILE", f.Name()+".missing")
+	assert.NotNil(t, passwordPolicyFromEnv())
+}
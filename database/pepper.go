@@ -0,0 +1,129 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// minPepperLength is the shortest secret accepted for a pepper, in bytes
+const minPepperLength = 16
+
+// Peppers are the secrets mixed into passwords before they're hashed, so a
+// copy of the database alone isn't enough to guess passwords from their
+// hashes. The first one peppers new passwords and the rest are kept to
+// check passwords peppered before a rotation, which are peppered again at
+// their next login. They're read from PASSWORD_PEPPER_FILE, without it
+// passwords aren't peppered.
+var Peppers []*Pepper
+
+// Pepper is a secret key passwords are combined with using HMAC-SHA256,
+// its ID is stored with each hash so that it can be rotated
+type Pepper struct {
+	ID     string
+	secret []byte
+}
+
+// NewPepper returns a pepper with the given ID and secret
+func NewPepper(ID string, secret []byte) *Pepper {
+	return &Pepper{ID: ID, secret: secret}
+}
+
+// apply returns what's hashed in place of password
+func (p *Pepper) apply(password string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(password))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// pepper returns password peppered with the pepper with the given ID, an
+// empty ID leaves it as it is
+func pepper(password, ID string) (string, error) {
+	if ID == "" {
+		return password, nil
+	}
+
+	for _, p := range Peppers {
+		if p.ID == ID {
+			return p.apply(password), nil
+		}
+	}
+
+	return "", ErrUnknownPepper
+}
+
+// currentPepperID returns the ID of the pepper new passwords are peppered
+// with, empty when there isn't one
+func currentPepperID() string {
+	if len(Peppers) == 0 {
+		return ""
+	}
+
+	return Peppers[0].ID
+}
+
+// ParsePeppers reads peppers from a file with one `<id>:<base64 secret>` a
+// line, the current pepper first. Blank lines and lines starting with #
+// are skipped.
+func ParsePeppers(b []byte) ([]*Pepper, error) {
+	peppers := []*Pepper{}
+	ids := map[string]bool{}
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; s.Scan(); line++ {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(l, ":", 2)
+		id := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || id == "" || strings.ContainsAny(id, " \t") {
+			return nil, fmt.Errorf("line %d: expected <id>:<base64 secret>", line)
+		}
+
+		if ids[id] {
+			return nil, fmt.Errorf("line %d: pepper %s is repeated", line, id)
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: secret isn't base64", line)
+		}
+
+		if len(secret) < minPepperLength {
+			return nil, fmt.Errorf("line %d: secret is shorter than %d bytes", line, minPepperLength)
+		}
+
+		ids[id] = true
+		peppers = append(peppers, NewPepper(id, secret))
+	}
+
+	return peppers, s.Err()
+}
+
+func peppersFromEnv() error {
+	path := os.Getenv("PASSWORD_PEPPER_FILE")
+	if path == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	peppers, err := ParsePeppers(b)
+	if err != nil {
+		return fmt.Errorf("password peppers %s: %v", path, err)
+	}
+
+	Peppers = peppers
+	return nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func withPeppers(peppers ...*Pepper) func() {
+	previous := Peppers
+	Peppers = peppers
+	return func() { Peppers = previous }
+}
+
+func secret(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestParsePeppers(t *testing.T) {
+	b := "# current first\n2017-12:" + secret("0123456789abcdef") + "\n\n2017-06 : " + secret("fedcba9876543210fedcba") + "\n"
+	peppers, err := ParsePeppers([]byte(b))
+	assert.Nil(t, err)
+	assert.Len(t, peppers, 2)
+	assert.Equal(t, "2017-12", peppers[0].ID)
+	assert.Equal(t, []byte("0123456789abcdef"), peppers[0].secret)
+	assert.Equal(t, "2017-06", peppers[1].ID)
+
+	invalid := []string{
+		"no secret",
+		":" + secret("0123456789abcdef"),
+		"a b:" + secret("0123456789abcdef"),
+		"short:" + secret("tooshort"),
+		"plain:0123456789abcdef0123456789abcdef!",
+		"dup:" + secret("0123456789abcdef") + "\ndup:" + secret("0123456789abcdef"),
+	}
+
+	for _, b := range invalid {
+		_, err := ParsePeppers([]byte(b))
+		assert.NotNil(t, err, b)
+	}
+}
+
+func TestPepperedPasswords(t *testing.T) {
+	defer withHasher(&Bcrypt{Cost: bcrypt.MinCost})()
+	defer withPeppers()()
+
+	// accounts from before peppers were set up
+	plain := &Account{}
+	assert.Nil(t, plain.HashPassword("password"))
+	assert.Empty(t, plain.PepperID)
+
+	old := NewPepper("old", []byte("0123456789abcdef"))
+	Peppers = []*Pepper{old}
+	assert.True(t, plain.PasswordOutdated())
+	assert.Nil(t, plain.ComparePasswordToHash("password"))
+
+	a := &Account{}
+	assert.Nil(t, a.HashPassword("password"))
+	assert.Equal(t, "old", a.PepperID)
+	assert.False(t, a.PasswordOutdated())
+	assert.Nil(t, a.ComparePasswordToHash("password"))
+	assert.Equal(t, ErrPasswordMismatch, a.ComparePasswordToHash("wrong"))
+
+	// the hash alone doesn't match the password
+	unpeppered := &Account{HashedPassword: a.HashedPassword}
+	assert.Equal(t, ErrPasswordMismatch, unpeppered.ComparePasswordToHash("password"))
+
+	// rotating keeps the old pepper working until the password is rehashed
+	Peppers = []*Pepper{NewPepper("new", []byte("fedcba9876543210")), old}
+	assert.Nil(t, a.ComparePasswordToHash("password"))
+	assert.True(t, a.PasswordOutdated())
+
+	assert.Nil(t, a.HashPassword("password"))
+	assert.Equal(t, "new", a.PepperID)
+	assert.False(t, a.PasswordOutdated())
+
+	// once dropped old passwords can't be checked
+	Peppers = Peppers[:1]
+	a.PepperID = "old"
+	assert.Equal(t, ErrUnknownPepper, a.ComparePasswordToHash("password"))
+}
+
+func TestPeppersFromEnv(t *testing.T) {
+	defer withPeppers()()
+	defer os.Unsetenv("PASSWORD_PEPPER_FILE")
+
+	f, err := ioutil.TempFile("", "peppers")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("current:" + secret(strings.Repeat("s", 32)) + "\n")
+	f.Close()
+
+	os.Setenv("PASSWORD_PEPPER_FILE", f.Name())
+	assert.Nil(t, peppersFromEnv())
+	assert.Equal(t, "current", currentPepperID())
+
+	os.Setenv("PASSWORD_PEPPER_FILE", f.Name()+".missing")
+	assert.NotNil(t, peppersFromEnv())
+}
@@ -1,10 +1,10 @@
 package database
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -27,15 +27,26 @@ func (a *Account) BeforeInsert(db orm.DB) error {
 
 		a.ConfirmationToken = t
 	}
+
+	a.hashTokens()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+
+	a.UpdatedAt = a.CreatedAt
+	if a.Version == 0 {
+		a.Version = 1
+	}
 	return nil
 }
 
 type PostgreSQL struct {
-	Database
 	db   *pg.DB
 	conn string
 }
 
+var _ Database = (*PostgreSQL)(nil)
+
 func (p *PostgreSQL) Connect(conn string) error {
 	p.conn = conn
 	opts, err := pg.ParseURL(conn)
@@ -73,35 +84,69 @@ func (p *PostgreSQL) Close() error {
 }
 
 func (p *PostgreSQL) Truncate() error {
-	p.db.Exec("TRUNCATE accounts;")
+	p.db.Exec("TRUNCATE accounts, sessions, mfa, login_failures, login_links;")
 	return nil
 }
 
-func (p *PostgreSQL) List(count32 int32, token string) (accounts []*Account, next_token string, err error) {
-	count := int(count32)
-	if token == "" {
-		token = "0"
-	}
-
-	offset, err := strconv.Atoi(token)
+func (p *PostgreSQL) List(count32 int32, token string, opts ListOptions) (accounts []*Account, next_token string, err error) {
+	count, order, cursor, err := listParams(count32, token, opts)
 	if err != nil {
 		return accounts, next_token, err
 	}
 
-	err = p.db.Model(&Account{}).
+	q := p.db.Model(&accounts).
 		Column("account.*").
-		Limit(count).
-		Offset(offset).
-		Select(&accounts)
+		Order(order.Column+" "+order.direction(), "id "+order.direction()).
+		Limit(count + 1)
 
-	if err != nil {
-		return accounts, next_token, err
+	if opts.EmailPrefix != "" {
+		q = q.Where("lower(email) LIKE ? ESCAPE '!'", likeEscape(strings.ToLower(opts.EmailPrefix))+"%")
+	}
+
+	if opts.NameContains != "" {
+		q = q.Where("name ILIKE ? ESCAPE '!'", "%"+likeEscape(opts.NameContains)+"%")
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		q = q.Where("created_at > ?", opts.CreatedAfter.UTC())
+	}
+
+	if !opts.CreatedBefore.IsZero() {
+		q = q.Where("created_at < ?", opts.CreatedBefore.UTC())
 	}
 
-	if len(accounts) == int(count) {
-		next_token = strconv.FormatInt(int64(offset+count+1), 10)
+	if opts.Confirmed != nil && *opts.Confirmed {
+		q = q.Where("confirmation_token IS NULL")
 	}
 
+	if opts.Confirmed != nil && !*opts.Confirmed {
+		q = q.Where("confirmation_token IS NOT NULL")
+	}
+
+	if len(opts.Metadata) > 0 {
+		md, err := json.Marshal(opts.Metadata)
+		if err != nil {
+			return accounts, next_token, err
+		}
+
+		q = q.Where("metadata::jsonb @> ?::jsonb", string(md))
+	}
+
+	if cursor.Offset > 0 {
+		q = q.Offset(cursor.Offset)
+	} else if cursor.ID != "" {
+		q = q.Where(
+			fmt.Sprintf("(%s, id) %s (?, ?)", order.Column, order.comparison()),
+			cursor.value(order), cursor.ID,
+		)
+	}
+
+	err = q.Select()
+	if err != nil {
+		return accounts, next_token, err
+	}
+
+	accounts, next_token = nextPage(accounts, count, opts, order)
 	return accounts, next_token, err
 }
 
@@ -121,7 +166,7 @@ func (p *PostgreSQL) ReadByID(ID string) (*Account, error) {
 
 func (p *PostgreSQL) ReadByEmail(email string) (*Account, error) {
 	a := Account{}
-	err := p.db.Model(&a).Where("email = ?", email).Select()
+	err := p.db.Model(&a).Where("lower(email) = lower(?)", email).Select()
 	if err != nil && notFoundError(err) {
 		return nil, ErrAccountNotFound
 	}
@@ -146,28 +191,53 @@ func (p *PostgreSQL) Create(a *Account, password string) error {
 	return nil
 }
 
-func (p *PostgreSQL) Update(a *Account) error {
-	err := a.Valid()
+func (p *PostgreSQL) Update(a *Account, fields ...string) error {
+	fields, err := updateFields(fields)
 	if err != nil {
 		return err
 	}
 
-	_, err = p.db.Model(&a).
-		Column("name", "email", "images").
-		Returning("*").
-		Update()
+	err = a.ValidFields(fields)
+	if err != nil {
+		return err
+	}
+
+	q := p.db.Model(&a).
+		Where("id = ?id").
+		Returning("*")
+
+	for _, f := range fields {
+		q = q.Set(f + " = ?" + f)
+
+		// the pepper is part of the password
+		if f == "hashed_password" {
+			q = q.Set("pepper_id = ?pepper_id")
+		}
+	}
+
+	q = q.Set("updated_at = (now() at time zone 'utc')")
+	q = q.Set("version = version + 1")
+	if a.Version != 0 {
+		q = q.Where("version = ?", a.Version)
+	}
+
+	res, err := q.Update()
 	if err != nil && uniqueEmailError(err) {
 		return ErrEmailExists
 	}
 
 	if err != nil && notFoundError(err) {
-		return ErrAccountNotFound
+		return versionError(p, a.ID)
 	}
 
 	if err != nil {
 		return err
 	}
 
+	if res.RowsAffected() == 0 {
+		return versionError(p, a.ID)
+	}
+
 	return nil
 }
 
@@ -184,7 +254,16 @@ func (p *PostgreSQL) GeneratePasswordToken(email string) (*Account, error) {
 	}
 
 	a.PasswordResetToken = t
-	err = p.db.Update(a)
+	a.PasswordResetTokenHash = HashToken(t)
+	a.PasswordResetExpiresAt = passwordResetExpiry()
+	_, err = p.db.Model(a).
+		Set("password_reset_token = ?password_reset_token").
+		Set("password_reset_expires_at = ?password_reset_expires_at").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Returning("*").
+		Update()
 	if err != nil {
 		return nil, err
 	}
@@ -192,10 +271,24 @@ func (p *PostgreSQL) GeneratePasswordToken(email string) (*Account, error) {
 	return a, nil
 }
 
-func (p *PostgreSQL) UpdatePassword(token, hashed_password string) (*Account, error) {
+func (p *PostgreSQL) ReadByPasswordToken(token string) (*Account, error) {
+	a := Account{}
+	err := p.db.Model(&a).Where("password_reset_token = ?", HashToken(token)).Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrAccountNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (p *PostgreSQL) UpdatePassword(token, hashed_password, pepperID string) (*Account, error) {
 	var a Account
 	err := p.db.Model(&a).
-		Where("password_reset_token = ?", token).
+		Where("password_reset_token = ?", HashToken(token)).
 		Select()
 	if err != nil && notFoundError(err) {
 		return nil, ErrAccountNotFound
@@ -205,19 +298,139 @@ func (p *PostgreSQL) UpdatePassword(token, hashed_password string) (*Account, er
 		return nil, err
 	}
 
+	if a.passwordResetExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the token as well makes sure it is only used once
 	a.HashedPassword = hashed_password
-	err = p.db.Update(&a)
+	a.PepperID = pepperID
+	res, err := p.db.Model(&a).
+		Set("hashed_password = ?hashed_password").
+		Set("pepper_id = ?pepper_id").
+		Set("password_reset_token = NULL").
+		Set("password_reset_expires_at = NULL").
+		Set("pending_email = NULL").
+		Set("email_change_token = NULL").
+		Set("email_change_expires_at = NULL").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Where("password_reset_token = ?", HashToken(token)).
+		Returning("*").
+		Update()
+	if err != nil && notFoundError(err) {
+		return nil, ErrAccountNotFound
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	if res.RowsAffected() == 0 {
+		return nil, ErrAccountNotFound
+	}
+
+	return &a, nil
+}
+
+func (p *PostgreSQL) RequestEmailChange(ID, email string) (*Account, error) {
+	a, err := p.ReadByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = emailInUse(p, ID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		logrus.Errorf("email change token generation error %v", err)
+		return nil, err
+	}
+
+	a.PendingEmail = email
+	a.EmailChangeToken = t
+	a.EmailChangeTokenHash = HashToken(t)
+	a.EmailChangeExpiresAt = emailChangeExpiry()
+	_, err = p.db.Model(a).
+		Set("pending_email = ?pending_email").
+		Set("email_change_token = ?email_change_token").
+		Set("email_change_expires_at = ?email_change_expires_at").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Returning("*").
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (p *PostgreSQL) ConfirmEmailChange(token string) (*Account, error) {
+	var a Account
+	err := p.db.Model(&a).
+		Where("email_change_token = ?", HashToken(token)).
+		Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrAccountNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if a.emailChangeExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// the email may have been taken since the change was requested
+	err = emailInUse(p, a.ID, a.PendingEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// matching on the token as well makes sure it is only used once
+	a.PreviousEmail = a.Email
+	a.Email = a.PendingEmail
+	res, err := p.db.Model(&a).
+		Set("email = ?email").
+		Set("pending_email = NULL").
+		Set("email_change_token = NULL").
+		Set("email_change_expires_at = NULL").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Where("email_change_token = ?email_change_token").
+		Returning("*").
+		Update()
+	if err != nil && uniqueEmailError(err) {
+		return nil, ErrEmailExists
+	}
+
+	if err != nil && notFoundError(err) {
+		return nil, ErrAccountNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrAccountNotFound
+	}
+
 	return &a, nil
 }
 
 func (p *PostgreSQL) Confirm(token string) (*Account, error) {
 	var a Account
 	err := p.db.Model(&a).
-		Where("confirmation_token = ?", token).
+		Where("confirmation_token = ?", HashToken(token)).
 		Select()
 	if err != nil && notFoundError(err) {
 		return nil, ErrAccountNotFound
@@ -227,27 +440,628 @@ func (p *PostgreSQL) Confirm(token string) (*Account, error) {
 		return nil, err
 	}
 
-	a.ConfirmationToken = ""
-	err = p.db.Update(&a)
+	if a.confirmationExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the token as well makes sure a token replaced in the
+	// meantime can't be used
+	res, err := p.db.Model(&a).
+		Set("confirmation_token = NULL").
+		Set("confirmation_expires_at = NULL").
+		Set("confirmed_at = (now() at time zone 'utc')").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Where("confirmation_token = ?confirmation_token").
+		Returning("*").
+		Update()
+	if err != nil && notFoundError(err) {
+		return nil, ErrAccountNotFound
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	if res.RowsAffected() == 0 {
+		return nil, ErrAccountNotFound
+	}
+
 	return &a, nil
 }
 
-func (p *PostgreSQL) Delete(ID string) error {
-	a := Account{ID: ID}
-	err := p.db.Delete(&a)
+func (p *PostgreSQL) GenerateConfirmationToken(email string) (*Account, error) {
+	a, err := p.ReadByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Confirmed() {
+		return nil, ErrConfirmed
+	}
+
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		logrus.Errorf("confirm token generation error %v", err)
+		return nil, err
+	}
+
+	// matching on the old token as well stops an account confirmed in the
+	// meantime getting a token again
+	previous := a.ConfirmationTokenHash
+	a.ConfirmationToken = t
+	a.ConfirmationTokenHash = HashToken(t)
+	a.ConfirmationExpiresAt = confirmationExpiry()
+	res, err := p.db.Model(a).
+		Set("confirmation_token = ?confirmation_token").
+		Set("confirmation_expires_at = ?confirmation_expires_at").
+		Set("updated_at = (now() at time zone 'utc')").
+		Set("version = version + 1").
+		Where("id = ?id").
+		Where("confirmation_token = ?", previous).
+		Returning("*").
+		Update()
+	if err != nil && notFoundError(err) {
+		return nil, ErrConfirmed
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrConfirmed
+	}
+
+	return a, nil
+}
+
+func (p *PostgreSQL) RecordLogin(a *Account) error {
+	res, err := p.db.Model(a).
+		Set("last_login_at = (now() at time zone 'utc')").
+		Where("id = ?id").
+		Returning("last_login_at").
+		Update()
+	if err != nil && notFoundError(err) {
+		return ErrAccountNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) Delete(ID string, version int64) error {
+	q := p.db.Model(&Account{ID: ID}).Where("id = ?id")
+	if version != 0 {
+		q = q.Where("version = ?", version)
+	}
+
+	res, err := q.Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 && version != 0 {
+		return versionError(p, ID)
+	}
+
+	_, err = p.db.Model(&Mfa{}).Where("account_id = ?", ID).Delete()
+	if err != nil {
+		return err
+	}
+
+	return p.RevokeAllSessions(ID)
+}
+
+func (p *PostgreSQL) CreateSession(s *Session) error {
+	err := s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return err
+	}
+
+	s.CreatedAt = s.LastSeenAt
+	return p.db.Insert(s)
+}
+
+func (p *PostgreSQL) RefreshSession(refreshToken string) (*Session, error) {
+	var s Session
+	err := p.db.Model(&s).
+		Where("refresh_token = ?", HashToken(refreshToken)).
+		Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrSessionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.refreshExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	old := s.RefreshTokenHash
+	err = s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return nil, err
+	}
+
+	// matching on the old refresh token as well makes sure it is only used once
+	res, err := p.db.Model(&s).
+		Column("access_token", "refresh_token", "last_seen_at",
+			"access_token_expires_at", "refresh_token_expires_at").
+		Where("id = ?id").
+		Where("refresh_token = ?", old).
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return &s, nil
+}
+
+func (p *PostgreSQL) TouchSession(accessToken string) (*Session, error) {
+	var s Session
+	err := p.db.Model(&s).
+		Where("access_token = ?", HashToken(accessToken)).
+		Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrSessionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.accessExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the access token as well misses sessions refreshed since
+	s.LastSeenAt = time.Now().UTC().Truncate(time.Microsecond)
+	res, err := p.db.Model(&s).
+		Column("last_seen_at").
+		Where("id = ?id").
+		Where("access_token = ?", s.AccessTokenHash).
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return &s, nil
+}
+
+func (p *PostgreSQL) ListSessions(accountID string) ([]*Session, error) {
+	sessions := []*Session{}
+	err := p.db.Model(&sessions).
+		Where("account_id = ?", accountID).
+		Where("refresh_token_expires_at > ?", time.Now().UTC()).
+		Order("last_seen_at DESC", "id").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (p *PostgreSQL) RevokeSession(ID string) error {
+	res, err := p.db.Model(&Session{}).Where("id = ?", ID).Delete()
 	if err != nil {
 		return err
 	}
 
+	if res.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
 	return nil
 }
 
+func (p *PostgreSQL) RevokeAllSessions(accountID string) error {
+	_, err := p.db.Model(&Session{}).Where("account_id = ?", accountID).Delete()
+	return err
+}
+
+func (p *PostgreSQL) ReadMfa(accountID string) (*Mfa, error) {
+	m := Mfa{AccountID: accountID}
+	err := p.db.Select(&m)
+	if err != nil && notFoundError(err) {
+		return nil, ErrMfaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (p *PostgreSQL) EnrolMfa(accountID, secret string) error {
+	m, err := p.ReadMfa(accountID)
+	if err != nil && err != ErrMfaNotFound {
+		return err
+	}
+
+	if m != nil && m.Enabled {
+		return ErrMfaEnabled
+	}
+
+	_, err = p.db.Model(&Mfa{}).
+		Where("account_id = ?", accountID).
+		Where("NOT enabled").
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	return p.db.Insert(&Mfa{
+		AccountID: accountID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (p *PostgreSQL) EnableMfa(accountID string, step int64) error {
+	m := Mfa{AccountID: accountID, Enabled: true, LastUsedStep: step}
+	res, err := p.db.Model(&m).
+		Column("enabled", "last_used_step").
+		Where("account_id = ?account_id").
+		Where("NOT enabled").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		_, err = p.ReadMfa(accountID)
+		if err != nil {
+			return err
+		}
+
+		return ErrMfaEnabled
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) DisableMfa(accountID string) error {
+	res, err := p.db.Model(&Mfa{}).Where("account_id = ?", accountID).Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrMfaNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) SetRecoveryCodes(accountID string, hashes []string) error {
+	m := Mfa{AccountID: accountID, RecoveryCodeHashes: hashes}
+	res, err := p.db.Model(&m).
+		Column("recovery_codes").
+		Where("account_id = ?account_id").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrMfaNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) UseMfaStep(accountID string, step int64) error {
+	m := Mfa{AccountID: accountID, LastUsedStep: step}
+	res, err := p.db.Model(&m).
+		Column("last_used_step").
+		Where("account_id = ?account_id").
+		Where("last_used_step < ?last_used_step").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		_, err = p.ReadMfa(accountID)
+		if err != nil {
+			return err
+		}
+
+		return ErrMfaCodeUsed
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) UseRecoveryCode(accountID, hash string) error {
+	m, err := p.ReadMfa(accountID)
+	if err != nil {
+		return err
+	}
+
+	hashes, ok := m.withoutRecoveryCode(hash)
+	if !ok {
+		return ErrMfaCodeUsed
+	}
+
+	before, err := json.Marshal(m.RecoveryCodeHashes)
+	if err != nil {
+		return err
+	}
+
+	// matching on the codes read makes sure a code can't be used twice by
+	// concurrent requests
+	m.RecoveryCodeHashes = hashes
+	res, err := p.db.Model(m).
+		Column("recovery_codes").
+		Where("account_id = ?account_id").
+		Where("recovery_codes = ?", string(before)).
+		Update()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrMfaCodeUsed
+	}
+
+	return nil
+}
+
+func (p *PostgreSQL) CreateMfaChallenge(accountID string) (*Mfa, error) {
+	m, err := p.ReadMfa(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.issueChallenge()
+	if err != nil {
+		logrus.Errorf("mfa challenge generation error %v", err)
+		return nil, err
+	}
+
+	_, err = p.db.Model(m).
+		Column("challenge", "challenge_expires_at").
+		Where("account_id = ?account_id").
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (p *PostgreSQL) ConsumeMfaChallenge(token string) (*Mfa, error) {
+	var m Mfa
+	err := p.db.Model(&m).
+		Where("challenge = ?", HashToken(token)).
+		Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrNoMfaChallenge
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// matching on the challenge as well makes sure it is only used once
+	res, err := p.db.Model(&m).
+		Set("challenge = NULL").
+		Set("challenge_expires_at = NULL").
+		Where("account_id = ?account_id").
+		Where("challenge = ?challenge").
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrNoMfaChallenge
+	}
+
+	if m.challengeExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	return &m, nil
+}
+
+func (p *PostgreSQL) ReadLoginFailures(subject string) (*LoginFailures, error) {
+	f := LoginFailures{Subject: subject}
+	err := p.db.Select(&f)
+	if err != nil && notFoundError(err) {
+		return &LoginFailures{Subject: subject}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (p *PostgreSQL) RecordLoginFailure(subject string) (*LoginFailures, error) {
+	var err error
+	for i := 0; i < loginFailureAttempts; i++ {
+		var f *LoginFailures
+		f, err = p.ReadLoginFailures(subject)
+		if err != nil {
+			return nil, err
+		}
+
+		exists := !f.LastFailedAt.IsZero()
+		previous := f.Failures
+		f.fail(time.Now().UTC().Truncate(time.Microsecond))
+
+		if !exists {
+			// fails if a concurrent failure inserted the subject first
+			err = p.db.Insert(f)
+			if err == nil {
+				return f, nil
+			}
+
+			continue
+		}
+
+		// matching on the previous count makes sure concurrent failures
+		// are all counted
+		var res orm.Result
+		res, err = p.db.Model(f).
+			Column("failures", "last_failed_at", "locked_until").
+			Where("subject = ?subject").
+			Where("failures = ?", previous).
+			Update()
+		if err != nil {
+			return nil, err
+		}
+
+		if res.RowsAffected() > 0 {
+			return f, nil
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ReadLoginFailures(subject)
+}
+
+func (p *PostgreSQL) ResetLoginFailures(subjects ...string) error {
+	if len(subjects) == 0 {
+		return nil
+	}
+
+	_, err := p.db.Model(&LoginFailures{}).
+		Where("subject IN (?)", pg.In(subjects)).
+		Delete()
+	return err
+}
+
+func (p *PostgreSQL) CreateLoginLink(email, accountID string) (*LoginLink, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	l, err := newLoginLink(email, accountID, now)
+	if err != nil {
+		logrus.Errorf("login link token generation error %v", err)
+		return nil, err
+	}
+
+	err = p.db.RunInTransaction(func(tx *pg.Tx) error {
+		// the lock is held until the transaction ends, so concurrent requests
+		// for the email count the links one at a time, even when there are
+		// no rows yet to lock
+		_, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "login_links:"+l.Email)
+		if err != nil {
+			return err
+		}
+
+		// links that no longer count towards the limit or work are kept no
+		// longer
+		_, err = tx.Model(&LoginLink{}).
+			Where("email = ?", l.Email).
+			Where("created_at <= ?", loginLinkWindowStart(now)).
+			Where("expires_at <= ?", now).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		var recent []LoginLink
+		err = tx.Model(&recent).
+			Column("created_at").
+			Where("email = ?", l.Email).
+			Where("created_at > ?", loginLinkWindowStart(now)).
+			Order("created_at").
+			Select()
+		if err != nil {
+			return err
+		}
+
+		created := make([]time.Time, len(recent))
+		for i, r := range recent {
+			created[i] = r.CreatedAt
+		}
+
+		err = loginLinkLimited(created, now)
+		if err != nil {
+			return err
+		}
+
+		return tx.Insert(l)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (p *PostgreSQL) ConsumeLoginLink(token string) (*LoginLink, error) {
+	var l LoginLink
+	err := p.db.Model(&l).
+		Where("token = ?", HashToken(token)).
+		Select()
+	if err != nil && notFoundError(err) {
+		return nil, ErrNoLoginLink
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l.AccountID == "" || !l.UsedAt.IsZero() {
+		return nil, ErrNoLoginLink
+	}
+
+	// only marking it used if it isn't already makes sure it is used once
+	l.UsedAt = time.Now().UTC().Truncate(time.Microsecond)
+	res, err := p.db.Model(&l).
+		Set("used_at = ?used_at").
+		Where("token = ?token").
+		Where("used_at IS NULL").
+		Update()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return nil, ErrNoLoginLink
+	}
+
+	if l.expired() {
+		return nil, ErrTokenExpired
+	}
+
+	return &l, nil
+}
+
+// uniqueEmailError reports whether err violates the accounts_email index
+// rather than another unique column with email in its name
 func uniqueEmailError(err error) bool {
-	return strings.Contains(err.Error(), "duplicate key value violates unique constraint") && strings.Contains(err.Error(), "email")
+	return strings.Contains(err.Error(), `duplicate key value violates unique constraint "accounts_email"`)
 }
 
 func notFoundError(err error) bool {
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"os"
+	"time"
+)
+
+var (
+	// SessionAccessTTL is how long a session access token is valid for, it
+	// can be changed with SESSION_ACCESS_TTL
+	SessionAccessTTL = time.Hour
+	// SessionRefreshTTL is how long a session can be refreshed for after it
+	// was last refreshed, it can be changed with SESSION_REFRESH_TTL
+	SessionRefreshTTL = 30 * 24 * time.Hour
+)
+
+// Session is a login on a device. Only the digests of its tokens are
+// stored, the tokens themselves are set on the session returned when they
+// are generated by CreateSession and RefreshSession.
+type Session struct {
+	ID                    string `db:"id"`
+	AccountID             string `db:"account_id"`
+	AccessToken           string `sql:"-"`
+	RefreshToken          string `sql:"-"`
+	AccessTokenHash       string `sql:"access_token"`
+	RefreshTokenHash      string `sql:"refresh_token"`
+	UserAgent             string
+	Device                string
+	CreatedAt             time.Time `db:"created_at"`
+	LastSeenAt            time.Time `db:"last_seen_at"`
+	AccessTokenExpiresAt  time.Time `db:"access_token_expires_at"`
+	RefreshTokenExpiresAt time.Time `db:"refresh_token_expires_at"`
+}
+
+// issueTokens generates a new pair of tokens for the session, the session
+// is seen as active from now on.
+func (s *Session) issueTokens() error {
+	access, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		return err
+	}
+
+	refresh, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	s.AccessToken = access
+	s.AccessTokenHash = HashToken(access)
+	s.RefreshToken = refresh
+	s.RefreshTokenHash = HashToken(refresh)
+	s.LastSeenAt = now
+	s.AccessTokenExpiresAt = now.Add(SessionAccessTTL)
+	s.RefreshTokenExpiresAt = now.Add(SessionRefreshTTL)
+	return nil
+}
+
+// accessExpired reports whether the access token of the session has expired
+func (s *Session) accessExpired() bool {
+	return !s.AccessTokenExpiresAt.After(time.Now())
+}
+
+// refreshExpired reports whether the session can no longer be refreshed
+func (s *Session) refreshExpired() bool {
+	return !s.RefreshTokenExpiresAt.After(time.Now())
+}
+
+func sessionTTLsFromEnv() {
+	if d, err := time.ParseDuration(os.Getenv("SESSION_ACCESS_TTL")); err == nil && d > 0 {
+		SessionAccessTTL = d
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("SESSION_REFRESH_TTL")); err == nil && d > 0 {
+		SessionRefreshTTL = d
+	}
+}
@@ -0,0 +1,1369 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// sqlDB implements Database on top of database/sql, the MySQL and SQLite
+// drivers embed it and only add what differs between their dialects:
+// connecting, migrating, truncating and the hooks below.
+type sqlDB struct {
+	db *sql.DB
+	// metadataExpr extracts a metadata value given a JSON path placeholder
+	metadataExpr string
+	// emailWhere matches accounts.email against a placeholder ignoring
+	// case, as the unique index on it does
+	emailWhere string
+	// uniqueEmailError reports whether err violates the unique index on
+	// accounts.email
+	uniqueEmailError func(err error) bool
+	// forUpdate is appended to selects to lock what they read until the
+	// transaction ends, it's empty where transactions already run one at a
+	// time
+	forUpdate string
+}
+
+// accountColumns are the columns selected by the database/sql based drivers,
+// in the order expected by scanAccount.
+const accountColumns = `id, name, email, hashed_password, pepper_id, created_at,
+	images, metadata, confirmation_token, password_reset_token,
+	password_reset_expires_at, confirmation_expires_at, pending_email,
+	email_change_token, email_change_expires_at, updated_at, confirmed_at,
+	last_login_at, version`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAccount(row rowScanner) (*Account, error) {
+	var a Account
+	var name, pepperID, images, metadata, confirm, reset sql.NullString
+	var pendingEmail, emailChange sql.NullString
+	var resetExpires, confirmExpires, emailChangeExpires *time.Time
+	var updatedAt, confirmedAt, lastLoginAt *time.Time
+
+	err := row.Scan(
+		&a.ID, &name, &a.Email, &a.HashedPassword, &pepperID, &a.CreatedAt,
+		&images, &metadata, &confirm, &reset,
+		&resetExpires, &confirmExpires, &pendingEmail,
+		&emailChange, &emailChangeExpires, &updatedAt, &confirmedAt,
+		&lastLoginAt, &a.Version,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrAccountNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	a.Name = name.String
+	a.PepperID = pepperID.String
+	a.ConfirmationTokenHash = confirm.String
+	a.PasswordResetTokenHash = reset.String
+	a.PendingEmail = pendingEmail.String
+	a.EmailChangeTokenHash = emailChange.String
+
+	if resetExpires != nil {
+		a.PasswordResetExpiresAt = resetExpires.UTC()
+	}
+
+	if confirmExpires != nil {
+		a.ConfirmationExpiresAt = confirmExpires.UTC()
+	}
+
+	if emailChangeExpires != nil {
+		a.EmailChangeExpiresAt = emailChangeExpires.UTC()
+	}
+
+	if updatedAt != nil {
+		a.UpdatedAt = updatedAt.UTC()
+	}
+
+	if confirmedAt != nil {
+		a.ConfirmedAt = confirmedAt.UTC()
+	}
+
+	if lastLoginAt != nil {
+		a.LastLoginAt = lastLoginAt.UTC()
+	}
+
+	if images.String != "" {
+		err = json.Unmarshal([]byte(images.String), &a.Images)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if metadata.String != "" {
+		err = json.Unmarshal([]byte(metadata.String), &a.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &a, nil
+}
+
+// nullString stores blank strings as NULL so that unique indexes on
+// optional columns such as tokens are not violated.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// jsonValue encodes v as JSON, storing nil slices and maps as NULL.
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(b) == "null" {
+		return nil, nil
+	}
+
+	return string(b), nil
+}
+
+// updateSet builds the SET clause and arguments updating fields of a, the
+// version is always incremented and updated_at set
+func updateSet(a *Account, fields []string) (string, []interface{}, error) {
+	set := []string{}
+	args := []interface{}{}
+
+	for _, f := range fields {
+		var arg interface{}
+		var err error
+		switch f {
+		case "name":
+			arg = a.Name
+		case "email":
+			arg = a.Email
+		case "images":
+			arg, err = jsonValue(a.Images)
+		case "metadata":
+			arg, err = jsonValue(a.Metadata)
+		case "hashed_password":
+			// the pepper is part of the password
+			arg = a.HashedPassword
+			set = append(set, "pepper_id = ?")
+			args = append(args, nullString(a.PepperID))
+		default:
+			err = ErrUnknownField
+		}
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		set = append(set, f+" = ?")
+		args = append(args, arg)
+	}
+
+	set = append(set, "updated_at = ?", "version = version + 1")
+	args = append(args, time.Now().UTC().Truncate(time.Microsecond))
+	return strings.Join(set, ", "), args, nil
+}
+
+// versionWhere restricts an UPDATE or DELETE by id to the expected version
+func versionWhere(ID string, version int64) (string, []interface{}) {
+	if version == 0 {
+		return " WHERE id = ?", []interface{}{ID}
+	}
+
+	return " WHERE id = ? AND version = ?", []interface{}{ID, version}
+}
+
+func (d *sqlDB) Close() error {
+	return d.db.Close()
+}
+
+func (d *sqlDB) List(count32 int32, token string, opts ListOptions) (accounts []*Account, next_token string, err error) {
+	count, order, cursor, err := listParams(count32, token, opts)
+	if err != nil {
+		return accounts, next_token, err
+	}
+
+	query, args := listQuery(count, opts, order, cursor, d.metadataExpr)
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return accounts, next_token, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return accounts, next_token, err
+		}
+
+		accounts = append(accounts, a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return accounts, next_token, err
+	}
+
+	accounts, next_token = nextPage(accounts, count, opts, order)
+	return accounts, next_token, err
+}
+
+func (d *sqlDB) ReadByID(ID string) (*Account, error) {
+	return scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE id = ?", ID,
+	))
+}
+
+func (d *sqlDB) ReadByEmail(email string) (*Account, error) {
+	return scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE "+d.emailWhere, email,
+	))
+}
+
+func (d *sqlDB) Create(a *Account, password string) error {
+	if a.ConfirmationToken == "" {
+		t, err := GenerateRandomString(TOKEN_LENGTH)
+		if err != nil {
+			logrus.Errorf("confirm token generation error %v", err)
+			return err
+		}
+
+		a.ConfirmationToken = t
+	}
+
+	a.hashTokens()
+	images, err := jsonValue(a.Images)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := jsonValue(a.Metadata)
+	if err != nil {
+		return err
+	}
+
+	id := uuid.NewV1().String()
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	_, err = d.db.Exec(
+		`INSERT INTO accounts (id, name, email, hashed_password, pepper_id, created_at, updated_at,
+			images, metadata, confirmation_token, confirmation_expires_at, password_reset_token, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`,
+		id, a.Name, a.Email, a.HashedPassword, nullString(a.PepperID), createdAt, createdAt,
+		images, metadata, nullString(a.ConfirmationTokenHash), nullTime(a.ConfirmationExpiresAt),
+		nullString(a.PasswordResetTokenHash),
+	)
+	if err != nil && d.uniqueEmailError(err) {
+		return ErrEmailExists
+	}
+
+	if err != nil {
+		return err
+	}
+
+	a.ID = id
+	a.CreatedAt = createdAt
+	a.UpdatedAt = createdAt
+	a.Version = 1
+	return nil
+}
+
+func (d *sqlDB) Update(a *Account, fields ...string) error {
+	fields, err := updateFields(fields)
+	if err != nil {
+		return err
+	}
+
+	err = a.ValidFields(fields)
+	if err != nil {
+		return err
+	}
+
+	set, args, err := updateSet(a, fields)
+	if err != nil {
+		return err
+	}
+
+	where, whereArgs := versionWhere(a.ID, a.Version)
+	res, err := d.db.Exec(
+		"UPDATE accounts SET "+set+where,
+		append(args, whereArgs...)...,
+	)
+	if err != nil && d.uniqueEmailError(err) {
+		return ErrEmailExists
+	}
+
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return versionError(d, a.ID)
+	}
+
+	ua, err := d.ReadByID(a.ID)
+	if err != nil {
+		return err
+	}
+
+	*a = *ua
+	return nil
+}
+
+func (d *sqlDB) Delete(ID string, version int64) error {
+	where, args := versionWhere(ID, version)
+	res, err := d.db.Exec("DELETE FROM accounts"+where, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 && version != 0 {
+		return versionError(d, ID)
+	}
+
+	_, err = d.db.Exec("DELETE FROM mfa WHERE account_id = ?", ID)
+	if err != nil {
+		return err
+	}
+
+	return d.RevokeAllSessions(ID)
+}
+
+func (d *sqlDB) GeneratePasswordToken(email string) (*Account, error) {
+	a, err := d.ReadByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		logrus.Errorf("password token generation error %v", err)
+		return nil, err
+	}
+
+	expires := passwordResetExpiry()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	_, err = d.db.Exec(
+		`UPDATE accounts SET password_reset_token = ?, password_reset_expires_at = ?,
+			updated_at = ?, version = version + 1 WHERE id = ?`, HashToken(t), expires, now, a.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a.PasswordResetToken = t
+	a.PasswordResetTokenHash = HashToken(t)
+	a.PasswordResetExpiresAt = expires
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) ReadByPasswordToken(token string) (*Account, error) {
+	return scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE password_reset_token = ?", HashToken(token),
+	))
+}
+
+func (d *sqlDB) UpdatePassword(token, hashed_password, pepperID string) (*Account, error) {
+	a, err := d.ReadByPasswordToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.passwordResetExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the token as well makes sure it is only used once
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec(
+		`UPDATE accounts SET hashed_password = ?, pepper_id = ?, password_reset_token = NULL,
+			password_reset_expires_at = NULL, pending_email = NULL, email_change_token = NULL,
+			email_change_expires_at = NULL, updated_at = ?, version = version + 1
+		WHERE id = ? AND password_reset_token = ?`, hashed_password, nullString(pepperID), now, a.ID, HashToken(token),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrAccountNotFound
+	}
+
+	a.HashedPassword = hashed_password
+	a.PepperID = pepperID
+	a.PasswordResetTokenHash = ""
+	a.PasswordResetExpiresAt = time.Time{}
+	a.cancelEmailChange()
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) Confirm(token string) (*Account, error) {
+	a, err := scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE confirmation_token = ?", HashToken(token),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if a.confirmationExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the token as well makes sure a token replaced in the
+	// meantime can't be used
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec(
+		`UPDATE accounts SET confirmation_token = NULL, confirmation_expires_at = NULL,
+			confirmed_at = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND confirmation_token = ?`, now, now, a.ID, a.ConfirmationTokenHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrAccountNotFound
+	}
+
+	a.ConfirmationTokenHash = ""
+	a.ConfirmationExpiresAt = time.Time{}
+	a.ConfirmedAt = now
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) GenerateConfirmationToken(email string) (*Account, error) {
+	a, err := scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE "+d.emailWhere, email,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Confirmed() {
+		return nil, ErrConfirmed
+	}
+
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		logrus.Errorf("confirm token generation error %v", err)
+		return nil, err
+	}
+
+	// matching on the old token as well stops an account confirmed in the
+	// meantime getting a token again
+	expires := confirmationExpiry()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec(
+		`UPDATE accounts SET confirmation_token = ?, confirmation_expires_at = ?,
+			updated_at = ?, version = version + 1 WHERE id = ? AND confirmation_token = ?`,
+		HashToken(t), expires, now, a.ID, a.ConfirmationTokenHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrConfirmed
+	}
+
+	a.ConfirmationToken = t
+	a.ConfirmationTokenHash = HashToken(t)
+	a.ConfirmationExpiresAt = expires
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) RequestEmailChange(ID, email string) (*Account, error) {
+	err := emailInUse(d, ID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE id = ?", ID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := GenerateRandomString(TOKEN_LENGTH)
+	if err != nil {
+		logrus.Errorf("email change token generation error %v", err)
+		return nil, err
+	}
+
+	expires := emailChangeExpiry()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	_, err = d.db.Exec(
+		`UPDATE accounts SET pending_email = ?, email_change_token = ?, email_change_expires_at = ?,
+			updated_at = ?, version = version + 1 WHERE id = ?`, email, HashToken(t), expires, now, a.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a.PendingEmail = email
+	a.EmailChangeToken = t
+	a.EmailChangeTokenHash = HashToken(t)
+	a.EmailChangeExpiresAt = expires
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) ConfirmEmailChange(token string) (*Account, error) {
+	a, err := scanAccount(d.db.QueryRow(
+		"SELECT "+accountColumns+" FROM accounts WHERE email_change_token = ?", HashToken(token),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if a.emailChangeExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// the email may have been taken since the change was requested
+	err = emailInUse(d, a.ID, a.PendingEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// matching on the token as well makes sure it is only used once
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec(
+		`UPDATE accounts SET email = ?, pending_email = NULL, email_change_token = NULL,
+			email_change_expires_at = NULL, updated_at = ?, version = version + 1
+		WHERE id = ? AND email_change_token = ?`, a.PendingEmail, now, a.ID, a.EmailChangeTokenHash,
+	)
+	if err != nil && d.uniqueEmailError(err) {
+		return nil, ErrEmailExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrAccountNotFound
+	}
+
+	a.PreviousEmail = a.Email
+	a.Email = a.PendingEmail
+	a.cancelEmailChange()
+	a.UpdatedAt = now
+	a.Version++
+	return a, nil
+}
+
+func (d *sqlDB) RecordLogin(a *Account) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec("UPDATE accounts SET last_login_at = ? WHERE id = ?", now, a.ID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrAccountNotFound
+	}
+
+	a.LastLoginAt = now
+	return nil
+}
+
+// listQuery builds the query used by List for the database/sql based
+// drivers. metadataExpr extracts a metadata value given a JSON path
+// placeholder as the drivers differ in their JSON functions.
+func listQuery(count int, opts ListOptions, order listOrder, cursor pageCursor, metadataExpr string) (string, []interface{}) {
+	where := []string{}
+	args := []interface{}{}
+
+	if opts.EmailPrefix != "" {
+		where = append(where, "lower(email) LIKE ? ESCAPE '!'")
+		args = append(args, likeEscape(strings.ToLower(opts.EmailPrefix))+"%")
+	}
+
+	if opts.NameContains != "" {
+		where = append(where, "lower(name) LIKE ? ESCAPE '!'")
+		args = append(args, "%"+likeEscape(strings.ToLower(opts.NameContains))+"%")
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		where = append(where, "created_at > ?")
+		args = append(args, opts.CreatedAfter.UTC())
+	}
+
+	if !opts.CreatedBefore.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, opts.CreatedBefore.UTC())
+	}
+
+	if opts.Confirmed != nil && *opts.Confirmed {
+		where = append(where, "confirmation_token IS NULL")
+	}
+
+	if opts.Confirmed != nil && !*opts.Confirmed {
+		where = append(where, "confirmation_token IS NOT NULL")
+	}
+
+	keys := make([]string, 0, len(opts.Metadata))
+	for k := range opts.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		where = append(where, metadataExpr+" = ?")
+		args = append(args, `$."`+strings.Replace(k, `"`, `\"`, -1)+`"`, opts.Metadata[k])
+	}
+
+	if cursor.ID != "" {
+		where = append(where, fmt.Sprintf(
+			"(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))",
+			order.Column, order.comparison(),
+		))
+		args = append(args, cursor.value(order), cursor.value(order), cursor.ID)
+	}
+
+	query := "SELECT " + accountColumns + " FROM accounts"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	query += fmt.Sprintf(
+		" ORDER BY %[1]s %[2]s, id %[2]s LIMIT ? OFFSET ?",
+		order.Column, order.direction(),
+	)
+	args = append(args, count+1, cursor.Offset)
+
+	return query, args
+}
+
+// sessionColumns are the columns of sessions in the order expected by
+// scanSession.
+const sessionColumns = `id, account_id, access_token, refresh_token, user_agent,
+	device, created_at, last_seen_at, access_token_expires_at, refresh_token_expires_at`
+
+func scanSession(row rowScanner) (*Session, error) {
+	var s Session
+	err := row.Scan(
+		&s.ID, &s.AccountID, &s.AccessTokenHash, &s.RefreshTokenHash, &s.UserAgent,
+		&s.Device, &s.CreatedAt, &s.LastSeenAt, &s.AccessTokenExpiresAt, &s.RefreshTokenExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrSessionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.CreatedAt = s.CreatedAt.UTC()
+	s.LastSeenAt = s.LastSeenAt.UTC()
+	s.AccessTokenExpiresAt = s.AccessTokenExpiresAt.UTC()
+	s.RefreshTokenExpiresAt = s.RefreshTokenExpiresAt.UTC()
+	return &s, nil
+}
+
+// The session methods are the same for every database/sql based driver
+
+func (d *sqlDB) CreateSession(s *Session) error {
+	err := s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return err
+	}
+
+	s.ID = uuid.NewV1().String()
+	s.CreatedAt = s.LastSeenAt
+
+	_, err = d.db.Exec(
+		"INSERT INTO sessions ("+sessionColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		s.ID, s.AccountID, s.AccessTokenHash, s.RefreshTokenHash, s.UserAgent,
+		s.Device, s.CreatedAt, s.LastSeenAt, s.AccessTokenExpiresAt, s.RefreshTokenExpiresAt,
+	)
+	return err
+}
+
+func (d *sqlDB) RefreshSession(refreshToken string) (*Session, error) {
+	s, err := scanSession(d.db.QueryRow(
+		"SELECT "+sessionColumns+" FROM sessions WHERE refresh_token = ?", HashToken(refreshToken),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.refreshExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	err = s.issueTokens()
+	if err != nil {
+		logrus.Errorf("session token generation error %v", err)
+		return nil, err
+	}
+
+	// matching on the old refresh token as well makes sure it is only used once
+	res, err := d.db.Exec(
+		`UPDATE sessions SET access_token = ?, refresh_token = ?, last_seen_at = ?,
+			access_token_expires_at = ?, refresh_token_expires_at = ?
+		WHERE id = ? AND refresh_token = ?`,
+		s.AccessTokenHash, s.RefreshTokenHash, s.LastSeenAt,
+		s.AccessTokenExpiresAt, s.RefreshTokenExpiresAt,
+		s.ID, HashToken(refreshToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return s, nil
+}
+
+func (d *sqlDB) TouchSession(accessToken string) (*Session, error) {
+	s, err := scanSession(d.db.QueryRow(
+		"SELECT "+sessionColumns+" FROM sessions WHERE access_token = ?", HashToken(accessToken),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.accessExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	// matching on the access token as well misses sessions refreshed since
+	s.LastSeenAt = time.Now().UTC().Truncate(time.Microsecond)
+	res, err := d.db.Exec(
+		"UPDATE sessions SET last_seen_at = ? WHERE id = ? AND access_token = ?",
+		s.LastSeenAt, s.ID, s.AccessTokenHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return s, nil
+}
+
+func (d *sqlDB) ListSessions(accountID string) ([]*Session, error) {
+	rows, err := d.db.Query(
+		"SELECT "+sessionColumns+` FROM sessions
+		WHERE account_id = ? AND refresh_token_expires_at > ?
+		ORDER BY last_seen_at DESC, id`,
+		accountID, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (d *sqlDB) RevokeSession(ID string) error {
+	res, err := d.db.Exec("DELETE FROM sessions WHERE id = ?", ID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (d *sqlDB) RevokeAllSessions(accountID string) error {
+	_, err := d.db.Exec("DELETE FROM sessions WHERE account_id = ?", accountID)
+	return err
+}
+
+// mfaColumns are the columns of mfa in the order expected by scanMfa
+const mfaColumns = `account_id, secret, enabled, recovery_codes, last_used_step,
+	challenge, challenge_expires_at, created_at`
+
+func scanMfa(row rowScanner) (*Mfa, error) {
+	var m Mfa
+	var codes, challenge sql.NullString
+	var challengeExpires *time.Time
+
+	err := row.Scan(
+		&m.AccountID, &m.Secret, &m.Enabled, &codes, &m.LastUsedStep,
+		&challenge, &challengeExpires, &m.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrMfaNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.ChallengeHash = challenge.String
+	m.CreatedAt = m.CreatedAt.UTC()
+
+	if challengeExpires != nil {
+		m.ChallengeExpiresAt = challengeExpires.UTC()
+	}
+
+	if codes.String != "" {
+		err = json.Unmarshal([]byte(codes.String), &m.RecoveryCodeHashes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &m, nil
+}
+
+// The MFA methods are the same for every database/sql based driver
+
+func (d *sqlDB) ReadMfa(accountID string) (*Mfa, error) {
+	return scanMfa(d.db.QueryRow(
+		"SELECT "+mfaColumns+" FROM mfa WHERE account_id = ?", accountID,
+	))
+}
+
+func (d *sqlDB) EnrolMfa(accountID, secret string) error {
+	m, err := d.ReadMfa(accountID)
+	if err != nil && err != ErrMfaNotFound {
+		return err
+	}
+
+	if m != nil && m.Enabled {
+		return ErrMfaEnabled
+	}
+
+	_, err = d.db.Exec("DELETE FROM mfa WHERE account_id = ? AND enabled = ?", accountID, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.Exec(
+		"INSERT INTO mfa (account_id, secret, enabled, last_used_step, created_at) VALUES (?, ?, ?, ?, ?)",
+		accountID, secret, false, 0, time.Now().UTC(),
+	)
+	return err
+}
+
+func (d *sqlDB) EnableMfa(accountID string, step int64) error {
+	res, err := d.db.Exec(
+		"UPDATE mfa SET enabled = ?, last_used_step = ? WHERE account_id = ? AND enabled = ?",
+		true, step, accountID, false,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		_, err = d.ReadMfa(accountID)
+		if err != nil {
+			return err
+		}
+
+		return ErrMfaEnabled
+	}
+
+	return nil
+}
+
+func (d *sqlDB) DisableMfa(accountID string) error {
+	res, err := d.db.Exec("DELETE FROM mfa WHERE account_id = ?", accountID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrMfaNotFound
+	}
+
+	return nil
+}
+
+func (d *sqlDB) SetRecoveryCodes(accountID string, hashes []string) error {
+	codes, err := json.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+
+	res, err := d.db.Exec("UPDATE mfa SET recovery_codes = ? WHERE account_id = ?", string(codes), accountID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrMfaNotFound
+	}
+
+	return nil
+}
+
+func (d *sqlDB) UseMfaStep(accountID string, step int64) error {
+	res, err := d.db.Exec(
+		"UPDATE mfa SET last_used_step = ? WHERE account_id = ? AND last_used_step < ?",
+		step, accountID, step,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		_, err = d.ReadMfa(accountID)
+		if err != nil {
+			return err
+		}
+
+		return ErrMfaCodeUsed
+	}
+
+	return nil
+}
+
+func (d *sqlDB) UseRecoveryCode(accountID, hash string) error {
+	m, err := d.ReadMfa(accountID)
+	if err != nil {
+		return err
+	}
+
+	hashes, ok := m.withoutRecoveryCode(hash)
+	if !ok {
+		return ErrMfaCodeUsed
+	}
+
+	before, err := json.Marshal(m.RecoveryCodeHashes)
+	if err != nil {
+		return err
+	}
+
+	after, err := json.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+
+	// matching on the codes read makes sure a code can't be used twice by
+	// concurrent requests
+	res, err := d.db.Exec(
+		"UPDATE mfa SET recovery_codes = ? WHERE account_id = ? AND recovery_codes = ?",
+		string(after), accountID, string(before),
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrMfaCodeUsed
+	}
+
+	return nil
+}
+
+func (d *sqlDB) CreateMfaChallenge(accountID string) (*Mfa, error) {
+	m, err := d.ReadMfa(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.issueChallenge()
+	if err != nil {
+		logrus.Errorf("mfa challenge generation error %v", err)
+		return nil, err
+	}
+
+	_, err = d.db.Exec(
+		"UPDATE mfa SET challenge = ?, challenge_expires_at = ? WHERE account_id = ?",
+		m.ChallengeHash, m.ChallengeExpiresAt, accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (d *sqlDB) ConsumeMfaChallenge(token string) (*Mfa, error) {
+	m, err := scanMfa(d.db.QueryRow(
+		"SELECT "+mfaColumns+" FROM mfa WHERE challenge = ?", HashToken(token),
+	))
+	if err == ErrMfaNotFound {
+		return nil, ErrNoMfaChallenge
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// matching on the challenge as well makes sure it is only used once
+	res, err := d.db.Exec(
+		"UPDATE mfa SET challenge = NULL, challenge_expires_at = NULL WHERE account_id = ? AND challenge = ?",
+		m.AccountID, m.ChallengeHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrNoMfaChallenge
+	}
+
+	if m.challengeExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	return m, nil
+}
+
+// loginFailureAttempts is how many times RecordLoginFailure retries when
+// the count is changed by a concurrent failure
+const loginFailureAttempts = 5
+
+func (d *sqlDB) ReadLoginFailures(subject string) (*LoginFailures, error) {
+	f := LoginFailures{Subject: subject}
+	var lastFailed, lockedUntil *time.Time
+
+	err := d.db.QueryRow(
+		"SELECT failures, last_failed_at, locked_until FROM login_failures WHERE subject = ?", subject,
+	).Scan(&f.Failures, &lastFailed, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return &f, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lastFailed != nil {
+		f.LastFailedAt = lastFailed.UTC()
+	}
+
+	if lockedUntil != nil {
+		f.LockedUntil = lockedUntil.UTC()
+	}
+
+	return &f, nil
+}
+
+func (d *sqlDB) RecordLoginFailure(subject string) (*LoginFailures, error) {
+	var err error
+	for i := 0; i < loginFailureAttempts; i++ {
+		var f *LoginFailures
+		f, err = d.ReadLoginFailures(subject)
+		if err != nil {
+			return nil, err
+		}
+
+		exists := !f.LastFailedAt.IsZero()
+		previous := f.Failures
+		f.fail(time.Now().UTC().Truncate(time.Microsecond))
+
+		if !exists {
+			// fails if a concurrent failure inserted the subject first
+			_, err = d.db.Exec(
+				"INSERT INTO login_failures (subject, failures, last_failed_at, locked_until) VALUES (?, ?, ?, ?)",
+				subject, f.Failures, f.LastFailedAt, nullTime(f.LockedUntil),
+			)
+			if err == nil {
+				return f, nil
+			}
+
+			continue
+		}
+
+		// matching on the previous count makes sure concurrent failures
+		// are all counted
+		var res sql.Result
+		res, err = d.db.Exec(
+			`UPDATE login_failures SET failures = ?, last_failed_at = ?, locked_until = ?
+			WHERE subject = ? AND failures = ?`,
+			f.Failures, f.LastFailedAt, nullTime(f.LockedUntil), subject, previous,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var n int64
+		n, err = res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if n > 0 {
+			return f, nil
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the subject is busy enough that other failures keep being counted
+	// first, which is just as good
+	return d.ReadLoginFailures(subject)
+}
+
+func (d *sqlDB) ResetLoginFailures(subjects ...string) error {
+	if len(subjects) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(subjects))
+	for i, s := range subjects {
+		args[i] = s
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(subjects)), ", ")
+	_, err := d.db.Exec("DELETE FROM login_failures WHERE subject IN ("+placeholders+")", args...)
+	return err
+}
+
+func (d *sqlDB) CreateLoginLink(email, accountID string) (*LoginLink, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	l, err := newLoginLink(email, accountID, now)
+	if err != nil {
+		logrus.Errorf("login link token generation error %v", err)
+		return nil, err
+	}
+
+	// the links are counted and the new one inserted in one transaction,
+	// locking the counted links so concurrent requests can't both pass
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// links that no longer count towards the limit or work are kept no longer
+	_, err = tx.Exec(
+		"DELETE FROM login_links WHERE email = ? AND created_at <= ? AND expires_at <= ?",
+		l.Email, loginLinkWindowStart(now), now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := recentLoginLinks(tx, l.Email, now, d.forUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loginLinkLimited(created, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO login_links (token, email, account_id, created_at, expires_at) VALUES (?, ?, ?, ?, ?)",
+		l.TokenHash, l.Email, nullString(l.AccountID), l.CreatedAt, l.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// recentLoginLinks returns when the links for email counting towards the
+// limit at now were created, oldest first
+func recentLoginLinks(tx *sql.Tx, email string, now time.Time, forUpdate string) ([]time.Time, error) {
+	rows, err := tx.Query(
+		"SELECT created_at FROM login_links WHERE email = ? AND created_at > ? ORDER BY created_at"+forUpdate,
+		email, loginLinkWindowStart(now),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	created := []time.Time{}
+	for rows.Next() {
+		var t time.Time
+		err = rows.Scan(&t)
+		if err != nil {
+			return nil, err
+		}
+
+		created = append(created, t.UTC())
+	}
+
+	return created, rows.Err()
+}
+
+func (d *sqlDB) ConsumeLoginLink(token string) (*LoginLink, error) {
+	l := LoginLink{TokenHash: HashToken(token)}
+	var accountID sql.NullString
+	var usedAt *time.Time
+
+	err := d.db.QueryRow(
+		"SELECT email, account_id, created_at, expires_at, used_at FROM login_links WHERE token = ?", l.TokenHash,
+	).Scan(&l.Email, &accountID, &l.CreatedAt, &l.ExpiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoLoginLink
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !accountID.Valid || usedAt != nil {
+		return nil, ErrNoLoginLink
+	}
+
+	l.AccountID = accountID.String
+	l.CreatedAt = l.CreatedAt.UTC()
+	l.ExpiresAt = l.ExpiresAt.UTC()
+	l.UsedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	// only marking it used if it isn't already makes sure it is used once
+	res, err := d.db.Exec(
+		"UPDATE login_links SET used_at = ? WHERE token = ? AND used_at IS NULL",
+		l.UsedAt, l.TokenHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, ErrNoLoginLink
+	}
+
+	if l.expired() {
+		return nil, ErrTokenExpired
+	}
+
+	return &l, nil
+}
+
+// nullTime stores zero times as NULL
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
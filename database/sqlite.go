@@ -0,0 +1,112 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/gemnasium/migrate/driver/sqlite3"
+	"github.com/gemnasium/migrate/migrate"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type SQLite struct {
+	sqlDB
+	path string
+}
+
+var _ Database = (*SQLite)(nil)
+
+func (s *SQLite) Connect(path string) error {
+	s.path = path
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+
+	// SQLite only allows a single writer, serialise access through one
+	// connection rather than returning "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	s.sqlDB = sqlDB{
+		db:               db,
+		metadataExpr:     "json_extract(metadata, ?)",
+		emailWhere:       "lower(email) = lower(?)",
+		uniqueEmailError: sqliteUniqueEmailError,
+	}
+	return nil
+}
+
+func (s *SQLite) Migrate() error {
+	wd := os.ExpandEnv("$GOPATH/src/github.com/lileio/account_service")
+	allErrors, ok := migrate.UpSync("sqlite3://"+s.path, wd+"/migrations/sqlite")
+	if !ok {
+		fmt.Printf("migration failed: %+v\n", allErrors)
+		return errors.New("migration error")
+	}
+
+	return s.hashPlaintextTokens()
+}
+
+// hashPlaintextTokens replaces tokens stored before only digests were kept.
+// SQLite has no SHA-256 function so unlike the other drivers this can't be
+// a SQL migration, digests are recognised by their length instead which
+// makes this safe to run on every Migrate.
+func (s *SQLite) hashPlaintextTokens() error {
+	for _, column := range []string{"confirmation_token", "password_reset_token"} {
+		rows, err := s.db.Query(
+			"SELECT id, "+column+" FROM accounts WHERE length("+column+") != ?",
+			sha256.Size*2,
+		)
+		if err != nil {
+			return err
+		}
+
+		tokens := map[string]string{}
+		for rows.Next() {
+			var id, token string
+			err = rows.Scan(&id, &token)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			tokens[id] = token
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		for id, token := range tokens {
+			_, err = s.db.Exec(
+				"UPDATE accounts SET "+column+" = ? WHERE id = ?", HashToken(token), id,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLite) Truncate() error {
+	s.db.Exec("DELETE FROM accounts;")
+	s.db.Exec("DELETE FROM sessions;")
+	s.db.Exec("DELETE FROM mfa;")
+	s.db.Exec("DELETE FROM login_failures;")
+	s.db.Exec("DELETE FROM login_links;")
+	return nil
+}
+
+// sqliteUniqueEmailError reports whether err violates the accounts_email
+// index rather than another unique column with email in its name
+func sqliteUniqueEmailError(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed: index 'accounts_email'")
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteHashesPlaintextTokens(t *testing.T) {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		t.Skip("SQLITE_PATH not set")
+	}
+
+	s := &SQLite{}
+	err := s.Connect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	assert.Nil(t, s.Migrate())
+	assert.Nil(t, s.Truncate())
+
+	a := Account{Name: "Alex", Email: "plaintext@localhost", HashedPassword: "hash"}
+	assert.Nil(t, s.Create(&a, "password"))
+
+	// store the token as it was before digests were used
+	_, err = s.db.Exec("UPDATE accounts SET confirmation_token = ? WHERE id = ?", "legacy", a.ID)
+	assert.Nil(t, err)
+
+	assert.Nil(t, s.Migrate())
+	assert.Nil(t, s.Migrate())
+
+	ca, err := s.Confirm("legacy")
+	assert.Nil(t, err)
+	assert.Equal(t, a.ID, ca.ID)
+}
+
+func TestSQLiteUniqueEmailError(t *testing.T) {
+	assert.True(t, sqliteUniqueEmailError(errors.New("UNIQUE constraint failed: index 'accounts_email'")))
+	assert.False(t, sqliteUniqueEmailError(errors.New("UNIQUE constraint failed: accounts.email_change_token")))
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
 // GenerateRandomBytes returns securely generated random bytes.
@@ -29,3 +31,15 @@ func GenerateRandomString(s int) (string, error) {
 	b, err := GenerateRandomBytes(s)
 	return base64.URLEncoding.EncodeToString(b), err
 }
+
+// HashToken returns the hex encoded SHA-256 digest of a token, only digests
+// of confirmation and password reset tokens are stored. Blank tokens hash to
+// a blank string so they never match.
+func HashToken(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+// Package jwt signs and verifies the JSON Web Tokens issued to accounts so
+// that other services can check who a request is from without calling
+// account_service.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	ErrNoKeys           = errors.New("no signing keys")
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+	// TTL is how long issued tokens are valid for, it can be changed with
+	// JWT_TTL
+	TTL = 15 * time.Minute
+	// Issuer is the iss claim of issued tokens, it can be changed with
+	// JWT_ISSUER
+	Issuer = "account_service"
+
+	encoding = base64.RawURLEncoding
+)
+
+// Claims are the claims carried by an account token
+type Claims struct {
+	Issuer        string `json:"iss"`
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	IssuedAt      int64  `json:"iat"`
+	ExpiresAt     int64  `json:"exp"`
+}
+
+// NewClaims returns the claims for an account, valid for TTL from now
+func NewClaims(accountID, email string, confirmed bool) Claims {
+	now := time.Now()
+	return Claims{
+		Issuer:        Issuer,
+		Subject:       accountID,
+		Email:         email,
+		EmailVerified: confirmed,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(TTL).Unix(),
+	}
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// sign returns the compact serialization of c signed with k
+func sign(k *Key, c Claims) (string, error) {
+	h, err := json.Marshal(header{Algorithm: k.Algorithm, Type: "JWT", KeyID: k.ID})
+	if err != nil {
+		return "", err
+	}
+
+	p, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	input := encoding.EncodeToString(h) + "." + encoding.EncodeToString(p)
+
+	var sig []byte
+	switch key := k.private.(type) {
+	case *rsa.PrivateKey:
+		sum := sha256.Sum256([]byte(input))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(key, []byte(input))
+	default:
+		err = ErrUnknownAlgorithm
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return input + "." + encoding.EncodeToString(sig), nil
+}
+
+// verify checks the signature of token using the key named in its header,
+// returning its claims if it is valid and hasn't expired
+func verify(keys func(kid string) *Key, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var h header
+	err := decodeSegment(parts[0], &h)
+	if err != nil {
+		return nil, err
+	}
+
+	k := keys(h.KeyID)
+	if k == nil {
+		return nil, ErrUnknownKey
+	}
+
+	// the algorithm is fixed by the key, never by the token
+	if h.Algorithm != k.Algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	input := []byte(parts[0] + "." + parts[1])
+
+	valid := false
+	switch key := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(input)
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) == nil
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, input, sig)
+	}
+
+	if !valid {
+		return nil, ErrInvalidToken
+	}
+
+	var c Claims
+	err = decodeSegment(parts[1], &c)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().Unix() >= c.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	return &c, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := encoding.DecodeString(seg)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if json.Unmarshal(b, v) != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+func settingsFromEnv() {
+	if d, err := time.ParseDuration(os.Getenv("JWT_TTL")); err == nil && d > 0 {
+		TTL = d
+	}
+
+	if iss := os.Getenv("JWT_ISSUER"); iss != "" {
+		Issuer = iss
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("JWT_PUBLISH_DELAY")); err == nil && d >= 0 {
+		PublishDelay = d
+	}
+}
@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "jwt")
+	assert.Nil(t, err)
+	return dir
+}
+
+func TestSignAndVerify(t *testing.T) {
+	for _, alg := range []string{RS256, EdDSA} {
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+
+		k, err := GenerateKey(dir, alg)
+		assert.Nil(t, err)
+
+		ks, err := LoadKeySet(dir)
+		assert.Nil(t, err)
+
+		token, err := ks.Sign(NewClaims("id", "alex@localhost", true))
+		assert.Nil(t, err)
+
+		c, err := ks.Verify(token)
+		assert.Nil(t, err)
+		assert.Equal(t, "id", c.Subject)
+		assert.Equal(t, "alex@localhost", c.Email)
+		assert.True(t, c.EmailVerified)
+		assert.Equal(t, Issuer, c.Issuer)
+
+		jwks := ks.PublicKeys()
+		assert.Equal(t, 1, len(jwks))
+		assert.Equal(t, k.ID, jwks[0].KeyID)
+		assert.Equal(t, alg, jwks[0].Algorithm)
+		assert.Equal(t, "sig", jwks[0].Use)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	_, err := GenerateKey(dir, EdDSA)
+	assert.Nil(t, err)
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	token, err := ks.Sign(NewClaims("id", "alex@localhost", false))
+	assert.Nil(t, err)
+
+	parts := strings.Split(token, ".")
+	other, err := ks.Sign(NewClaims("other", "alex@localhost", true))
+	assert.Nil(t, err)
+
+	forged := parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2]
+	_, err = ks.Verify(forged)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = ks.Verify("not a token")
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestVerifyExpired(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	_, err := GenerateKey(dir, EdDSA)
+	assert.Nil(t, err)
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	c := NewClaims("id", "alex@localhost", true)
+	c.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	token, err := ks.Sign(c)
+	assert.Nil(t, err)
+
+	_, err = ks.Verify(token)
+	assert.Equal(t, ErrTokenExpired, err)
+}
+
+func TestRotate(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	defer func(d time.Duration) { PublishDelay = d }(PublishDelay)
+	PublishDelay = 0
+
+	first, err := GenerateKey(dir, EdDSA)
+	assert.Nil(t, err)
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	old, err := ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Nil(t, err)
+
+	// key ids sort by the second they were generated in
+	time.Sleep(time.Second)
+	second, err := Rotate(dir, RS256, 2)
+	assert.Nil(t, err)
+	assert.Nil(t, ks.Reload())
+
+	// tokens signed before the rotation still verify
+	_, err = ks.Verify(old)
+	assert.Nil(t, err)
+
+	jwks := ks.PublicKeys()
+	assert.Equal(t, 2, len(jwks))
+	assert.Equal(t, second.ID, jwks[0].KeyID)
+	assert.Equal(t, first.ID, jwks[1].KeyID)
+
+	token, err := ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Nil(t, err)
+
+	// the newest key signs
+	var h header
+	assert.Nil(t, decodeSegment(strings.Split(token, ".")[0], &h))
+	assert.Equal(t, second.ID, h.KeyID)
+	assert.Equal(t, RS256, h.Algorithm)
+
+	time.Sleep(time.Second)
+	_, err = Rotate(dir, EdDSA, 2)
+	assert.Nil(t, err)
+	assert.Nil(t, ks.Reload())
+
+	_, err = ks.Verify(old)
+	assert.Equal(t, ErrUnknownKey, err)
+	assert.Equal(t, 2, len(ks.PublicKeys()))
+}
+
+func TestRotatePublishesBeforeSigning(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	defer func(d time.Duration) { PublishDelay = d }(PublishDelay)
+	PublishDelay = time.Hour
+
+	first, err := GenerateKey(dir, EdDSA)
+	assert.Nil(t, err)
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	time.Sleep(time.Second)
+	second, err := Rotate(dir, EdDSA, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, ks.Reload())
+
+	// the new key is published straight away but the key signing tokens is
+	// kept and carries on signing until the new key has been published for
+	// PublishDelay
+	jwks := ks.PublicKeys()
+	assert.Equal(t, 2, len(jwks))
+	assert.Equal(t, second.ID, jwks[0].KeyID)
+
+	token, err := ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Nil(t, err)
+
+	var h header
+	assert.Nil(t, decodeSegment(strings.Split(token, ".")[0], &h))
+	assert.Equal(t, first.ID, h.KeyID)
+
+	PublishDelay = 0
+	token, err = ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Nil(t, err)
+	assert.Nil(t, decodeSegment(strings.Split(token, ".")[0], &h))
+	assert.Equal(t, second.ID, h.KeyID)
+}
+
+func TestKeysNamedByHandWaitToSign(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	defer func(d time.Duration) { PublishDelay = d }(PublishDelay)
+	PublishDelay = time.Hour
+
+	first, err := GenerateKey(dir, EdDSA)
+	assert.Nil(t, err)
+
+	old := time.Now().Add(-2 * time.Hour)
+	file := filepath.Join(dir, first.ID+".pem")
+	assert.Nil(t, os.Chtimes(file, old, old))
+
+	// a key added by hand sorts after timestamp IDs but has only just been
+	// published
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "manual.pem"), b, 0600))
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	token, err := ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Nil(t, err)
+
+	var h header
+	assert.Nil(t, decodeSegment(strings.Split(token, ".")[0], &h))
+	assert.Equal(t, first.ID, h.KeyID)
+	assert.Equal(t, "manual", ks.PublicKeys()[0].KeyID)
+}
+
+func TestSignNoKeys(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	ks, err := LoadKeySet(dir)
+	assert.Nil(t, err)
+
+	_, err = ks.Sign(NewClaims("id", "alex@localhost", true))
+	assert.Equal(t, ErrNoKeys, err)
+}
@@ -0,0 +1,340 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RSAKeySize is the size of generated RS256 keys
+var RSAKeySize = 2048
+
+// PublishDelay is how long a new key is published in the JWKS before it signs
+// tokens, so that verifiers caching the JWKS have it by the time they see a
+// token signed with it. It can be changed with JWT_PUBLISH_DELAY and should be
+// at least as long as verifiers cache the JWKS for.
+var PublishDelay = time.Hour
+
+// keyIDTime is the layout of the timestamp key IDs start with
+const keyIDTime = "20060102T150405Z"
+
+// Key is a private signing key, its ID is the name of the file it was loaded
+// from without the .pem extension
+type Key struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+	// published is when the key's file was last written, it signs tokens
+	// once it has been published for PublishDelay
+	published time.Time
+}
+
+// KeySet holds the signing keys kept in a directory as PKCS #8 PEM files.
+// The newest key published for PublishDelay signs tokens and the others are
+// kept to verify tokens issued before a rotation.
+type KeySet struct {
+	dir  string
+	mu   sync.RWMutex
+	keys []*Key
+}
+
+// KeySetFromEnv loads the keys in JWT_KEYS_DIR, nil is returned if it isn't
+// set.
+func KeySetFromEnv() (*KeySet, error) {
+	settingsFromEnv()
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	return LoadKeySet(dir)
+}
+
+// LoadKeySet loads the keys in dir
+func LoadKeySet(dir string) (*KeySet, error) {
+	ks := &KeySet{dir: dir}
+	err := ks.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Reload reads the keys in the directory again, picking up rotated keys
+func (ks *KeySet) Reload() error {
+	keys, err := readKeys(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the keys every interval, it doesn't return
+func (ks *KeySet) Watch(interval time.Duration) {
+	for range time.Tick(interval) {
+		err := ks.Reload()
+		if err != nil {
+			logrus.Errorf("jwt key reload error %v", err)
+		}
+	}
+}
+
+// Sign signs c with the current signing key
+func (ks *KeySet) Sign(c Claims) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if len(ks.keys) == 0 {
+		return "", ErrNoKeys
+	}
+
+	return sign(ks.keys[signingKey(ks.keys, time.Now())], c)
+}
+
+// Verify checks token was signed by one of the keys and hasn't expired
+func (ks *KeySet) Verify(token string) (*Claims, error) {
+	return verify(ks.key, token)
+}
+
+func (ks *KeySet) key(kid string) *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, k := range ks.keys {
+		if k.ID == kid {
+			return k
+		}
+	}
+
+	return nil
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// PublicKeys returns the public half of every key, newest first
+func (ks *KeySet) PublicKeys() []JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := make([]JWK, 0, len(ks.keys))
+	for i := len(ks.keys) - 1; i >= 0; i-- {
+		jwks = append(jwks, ks.keys[i].publicJWK())
+	}
+
+	return jwks
+}
+
+func (k *Key) publicJWK() JWK {
+	jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Algorithm}
+
+	switch pub := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encoding.EncodeToString(pub.N.Bytes())
+		jwk.E = encoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// GenerateKey writes a new key for alg to dir, it becomes the signing key
+// PublishDelay after it was written, or straight away if dir has no other
+// keys.
+func GenerateKey(dir, alg string) (*Key, error) {
+	var private crypto.Signer
+	var err error
+
+	switch alg {
+	case RS256:
+		private, err = rsa.GenerateKey(rand.Reader, RSAKeySize)
+	case EdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = ErrUnknownAlgorithm
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	k := &Key{
+		ID:        now.UTC().Format(keyIDTime) + "-" + hex.EncodeToString(suffix),
+		Algorithm: alg,
+		private:   private,
+		published: now,
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	b := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	err = ioutil.WriteFile(filepath.Join(dir, k.ID+".pem"), b, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Rotate generates a new signing key in dir and removes all but the newest
+// keep keys. Keep enough keys to verify tokens issued before the rotation
+// until they expire. The key signing tokens is never removed, the new key
+// only takes over from it after PublishDelay, which is read from
+// JWT_PUBLISH_DELAY as running servers do.
+func Rotate(dir, alg string, keep int) (*Key, error) {
+	settingsFromEnv()
+
+	k, err := GenerateKey(dir, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := readKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if keep < 1 {
+		keep = 1
+	}
+
+	signing := signingKey(keys, time.Now())
+	for i := 0; i < len(keys)-keep && i < signing; i++ {
+		err = os.Remove(filepath.Join(dir, keys[i].ID+".pem"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// signingKey returns the index of the newest key published at least
+// PublishDelay before now, falling back to the oldest key while none has been
+// published that long. keys must be sorted by readKeys and not empty.
+func signingKey(keys []*Key, now time.Time) int {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if !keys[i].published.Add(PublishDelay).After(now) {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// readKeys returns the keys in dir oldest first, by when their files were
+// written and then by ID. Keys are ordered by their files rather than their
+// IDs so that keys added by hand, whatever they're named, wait PublishDelay
+// like generated ones.
+func readKeys(dir string) ([]*Key, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(files))
+	for _, f := range files {
+		k, err := readKey(f)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].published.Equal(keys[j].published) {
+			return keys[i].published.Before(keys[j].published)
+		}
+
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+func readKey(file string) (*Key, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Key{
+		ID:        strings.TrimSuffix(filepath.Base(file), ".pem"),
+		published: info.ModTime(),
+	}
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		k.Algorithm = RS256
+		k.private = key
+	case ed25519.PrivateKey:
+		k.Algorithm = EdDSA
+		k.private = key
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	return k, nil
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS accounts (
+	id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	name VARCHAR(255) NULL,
+	email VARCHAR(255) NOT NULL COLLATE utf8mb4_unicode_ci,
+	hashed_password VARCHAR(255) NOT NULL,
+	created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	images JSON NULL,
+	metadata JSON NULL,
+	confirmation_token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NULL,
+	password_reset_token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NULL,
+	PRIMARY KEY (id),
+	UNIQUE KEY accounts_email (email),
+	UNIQUE KEY accounts_confirmation_token (confirmation_token),
+	UNIQUE KEY accounts_reset_token (password_reset_token)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+CREATE INDEX accounts_created_at_id ON accounts (created_at, id);
@@ -0,0 +1 @@
+CREATE INDEX accounts_name_id ON accounts (name, id);
@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN password_reset_expires_at DATETIME(6) NULL;
+
+-- tokens without an expiry are rejected, give outstanding ones a day
+UPDATE accounts SET password_reset_expires_at = UTC_TIMESTAMP(6) + INTERVAL 1 DAY
+	WHERE password_reset_token IS NOT NULL;
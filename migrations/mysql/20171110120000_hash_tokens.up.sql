@@ -0,0 +1,5 @@
+-- only the SHA-256 digests of tokens are stored from now on
+UPDATE accounts SET confirmation_token = SHA2(confirmation_token, 256)
+	WHERE confirmation_token IS NOT NULL;
+UPDATE accounts SET password_reset_token = SHA2(password_reset_token, 256)
+	WHERE password_reset_token IS NOT NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sessions (
+	id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	account_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	access_token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	refresh_token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	user_agent TEXT NOT NULL,
+	device TEXT NOT NULL,
+	created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	last_seen_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	access_token_expires_at DATETIME(6) NOT NULL,
+	refresh_token_expires_at DATETIME(6) NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE KEY sessions_access_token (access_token),
+	UNIQUE KEY sessions_refresh_token (refresh_token),
+	KEY sessions_account_id (account_id, last_seen_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS mfa (
+	account_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	secret VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT FALSE,
+	recovery_codes TEXT CHARACTER SET ascii COLLATE ascii_bin,
+	last_used_step BIGINT NOT NULL DEFAULT 0,
+	challenge VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin,
+	challenge_expires_at DATETIME(6),
+	created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	PRIMARY KEY (account_id),
+	UNIQUE KEY mfa_challenge (challenge)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS login_failures (
+	subject VARCHAR(400) NOT NULL,
+	failures INT NOT NULL DEFAULT 0,
+	last_failed_at DATETIME(6) NOT NULL,
+	locked_until DATETIME(6),
+	PRIMARY KEY (subject)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_links (
+	token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	account_id CHAR(36) CHARACTER SET ascii COLLATE ascii_bin,
+	created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+	expires_at DATETIME(6) NOT NULL,
+	used_at DATETIME(6),
+	PRIMARY KEY (token),
+	KEY login_links_email (email, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
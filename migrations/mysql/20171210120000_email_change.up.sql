@@ -0,0 +1,4 @@
+ALTER TABLE accounts ADD COLUMN pending_email VARCHAR(255) NULL COLLATE utf8mb4_unicode_ci;
+ALTER TABLE accounts ADD COLUMN email_change_token VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NULL;
+ALTER TABLE accounts ADD COLUMN email_change_expires_at DATETIME(6) NULL;
+ALTER TABLE accounts ADD UNIQUE KEY accounts_email_change_token (email_change_token);
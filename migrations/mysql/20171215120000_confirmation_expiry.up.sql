@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN confirmation_expires_at DATETIME(6) NULL;
+
+-- tokens without an expiry are rejected, give outstanding ones a week
+UPDATE accounts SET confirmation_expires_at = UTC_TIMESTAMP(6) + INTERVAL 7 DAY
+	WHERE confirmation_token IS NOT NULL;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS accounts_created_at_id ON accounts (created_at, id);
@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- email_prefix
+CREATE INDEX IF NOT EXISTS accounts_email_prefix ON accounts (lower(email) text_pattern_ops);
+-- name_contains
+CREATE INDEX IF NOT EXISTS accounts_name_trgm ON accounts USING gin (name gin_trgm_ops);
+-- metadata equality
+CREATE INDEX IF NOT EXISTS accounts_metadata ON accounts USING gin ((metadata::jsonb) jsonb_path_ops);
+-- confirmed status
+CREATE INDEX IF NOT EXISTS accounts_unconfirmed ON accounts (created_at, id) WHERE confirmation_token IS NOT NULL;
+-- order_by
+CREATE INDEX IF NOT EXISTS accounts_email_id ON accounts (email, id);
+CREATE INDEX IF NOT EXISTS accounts_name_id ON accounts (name, id);
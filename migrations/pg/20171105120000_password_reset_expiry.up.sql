@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN password_reset_expires_at timestamp without time zone;
+
+-- tokens without an expiry are rejected, give outstanding ones a day
+UPDATE accounts SET password_reset_expires_at = (now() at time zone 'utc') + interval '1 day'
+	WHERE password_reset_token IS NOT NULL;
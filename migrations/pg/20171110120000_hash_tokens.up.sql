@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+-- only the SHA-256 digests of tokens are stored from now on
+UPDATE accounts SET confirmation_token = encode(digest(confirmation_token, 'sha256'), 'hex')
+	WHERE confirmation_token IS NOT NULL;
+UPDATE accounts SET password_reset_token = encode(digest(password_reset_token, 'sha256'), 'hex')
+	WHERE password_reset_token IS NOT NULL;
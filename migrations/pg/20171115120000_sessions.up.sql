@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sessions (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+	account_id UUID NOT NULL,
+	access_token text NOT NULL,
+	refresh_token text NOT NULL,
+	user_agent text NOT NULL DEFAULT '',
+	device text NOT NULL DEFAULT '',
+	created_at timestamp without time zone NOT NULL DEFAULT (now() at time zone 'utc'),
+	last_seen_at timestamp without time zone NOT NULL DEFAULT (now() at time zone 'utc'),
+	access_token_expires_at timestamp without time zone NOT NULL,
+	refresh_token_expires_at timestamp without time zone NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_access_token ON sessions (access_token);
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_refresh_token ON sessions (refresh_token);
+CREATE INDEX IF NOT EXISTS sessions_account_id ON sessions (account_id, last_seen_at);
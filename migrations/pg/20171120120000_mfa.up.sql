@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS mfa (
+	account_id UUID PRIMARY KEY,
+	secret text NOT NULL,
+	enabled boolean NOT NULL DEFAULT false,
+	recovery_codes text,
+	last_used_step bigint NOT NULL DEFAULT 0,
+	challenge text,
+	challenge_expires_at timestamp without time zone,
+	created_at timestamp without time zone NOT NULL DEFAULT (now() at time zone 'utc')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS mfa_challenge ON mfa (challenge);
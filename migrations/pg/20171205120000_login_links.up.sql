@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_links (
+	token text PRIMARY KEY,
+	email text NOT NULL,
+	account_id UUID,
+	created_at timestamp without time zone NOT NULL DEFAULT (now() at time zone 'utc'),
+	expires_at timestamp without time zone NOT NULL,
+	used_at timestamp without time zone
+);
+
+CREATE INDEX IF NOT EXISTS login_links_email ON login_links (email, created_at);
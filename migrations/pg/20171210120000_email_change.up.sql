@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN pending_email text;
+ALTER TABLE accounts ADD COLUMN email_change_token text;
+ALTER TABLE accounts ADD COLUMN email_change_expires_at timestamp without time zone;
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_email_change_token ON accounts (email_change_token);
@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN confirmation_expires_at timestamp without time zone;
+
+-- tokens without an expiry are rejected, give outstanding ones a week
+UPDATE accounts SET confirmation_expires_at = (now() at time zone 'utc') + interval '7 days'
+	WHERE confirmation_token IS NOT NULL;
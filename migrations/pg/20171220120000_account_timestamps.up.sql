@@ -0,0 +1,5 @@
+-- when existing accounts were last written to, confirmed or logged in to
+-- wasn't recorded, they're left empty until it next happens
+ALTER TABLE accounts ADD COLUMN updated_at timestamp without time zone;
+ALTER TABLE accounts ADD COLUMN confirmed_at timestamp without time zone;
+ALTER TABLE accounts ADD COLUMN last_login_at timestamp without time zone;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS accounts (
+	id text PRIMARY KEY,
+	name text NULL,
+	email text NOT NULL,
+	hashed_password text NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	images text,
+	metadata text,
+	confirmation_token text,
+	password_reset_token text
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_email ON accounts (lower(email));
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_confirmation_token ON accounts (confirmation_token);
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_reset_token ON accounts (password_reset_token);
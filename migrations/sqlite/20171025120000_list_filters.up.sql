@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS accounts_name_id ON accounts (name, id);
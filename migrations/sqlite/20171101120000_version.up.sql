@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN password_reset_expires_at timestamp NULL;
+
+-- tokens without an expiry are rejected, give outstanding ones a day
+UPDATE accounts SET password_reset_expires_at = datetime('now', '+1 day')
+	WHERE password_reset_token IS NOT NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sessions (
+	id text PRIMARY KEY,
+	account_id text NOT NULL,
+	access_token text NOT NULL,
+	refresh_token text NOT NULL,
+	user_agent text NOT NULL DEFAULT '',
+	device text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_seen_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	access_token_expires_at timestamp NOT NULL,
+	refresh_token_expires_at timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_access_token ON sessions (access_token);
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_refresh_token ON sessions (refresh_token);
+CREATE INDEX IF NOT EXISTS sessions_account_id ON sessions (account_id, last_seen_at);
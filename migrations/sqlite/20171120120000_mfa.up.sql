@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS mfa (
+	account_id text PRIMARY KEY,
+	secret text NOT NULL,
+	enabled boolean NOT NULL DEFAULT 0,
+	recovery_codes text,
+	last_used_step integer NOT NULL DEFAULT 0,
+	challenge text,
+	challenge_expires_at timestamp,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS mfa_challenge ON mfa (challenge);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS login_failures (
+	subject text PRIMARY KEY,
+	failures integer NOT NULL DEFAULT 0,
+	last_failed_at timestamp NOT NULL,
+	locked_until timestamp
+);
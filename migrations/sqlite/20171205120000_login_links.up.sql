@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_links (
+	token text PRIMARY KEY,
+	email text NOT NULL,
+	account_id text,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	expires_at timestamp NOT NULL,
+	used_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS login_links_email ON login_links (email, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN pending_email text NULL;
+ALTER TABLE accounts ADD COLUMN email_change_token text NULL;
+ALTER TABLE accounts ADD COLUMN email_change_expires_at timestamp NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_email_change_token ON accounts (email_change_token);
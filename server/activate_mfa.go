@@ -0,0 +1,44 @@
+package server
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/totp"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) ActivateMfa(ctx context.Context, r *account_service.ActivateMfaRequest) (*empty.Empty, error) {
+	m, err := as.DB.ReadMfa(r.AccountId)
+	if err != nil {
+		if err == database.ErrMfaNotFound {
+			return nil, ErrMfaNotEnrolled
+		}
+		return nil, err
+	}
+
+	if m.Enabled {
+		return nil, ErrMfaEnabled
+	}
+
+	// a valid code shows the secret was added to an authenticator app
+	step, ok := totp.Validate(m.Secret, r.Code, time.Now())
+	if !ok {
+		return nil, ErrMfaCodeIncorrect
+	}
+
+	err = as.DB.EnableMfa(m.AccountID, step)
+	if err != nil {
+		if err == database.ErrMfaEnabled {
+			return nil, ErrMfaEnabled
+		}
+		if err == database.ErrMfaNotFound {
+			return nil, ErrMfaNotEnrolled
+		}
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
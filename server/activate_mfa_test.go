@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestActivateMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	res, err := as.EnrolMfa(ctx, &account_service.EnrolMfaRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+
+	req := &account_service.ActivateMfaRequest{AccountId: a.Id, Code: mfaCode(t, res.Secret, 0)}
+	_, err = as.ActivateMfa(ctx, req)
+	assert.Nil(t, err)
+
+	_, err = as.ActivateMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
+
+func TestActivateMfaWrongCode(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	res, err := as.EnrolMfa(ctx, &account_service.EnrolMfaRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+
+	req := &account_service.ActivateMfaRequest{AccountId: a.Id, Code: mfaCode(t, res.Secret, 5)}
+	_, err = as.ActivateMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestActivateMfaNotEnrolled(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.ActivateMfaRequest{AccountId: a.Id, Code: "123456"}
+	_, err := as.ActivateMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -2,25 +2,192 @@ package server
 
 import (
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/sirupsen/logrus"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 )
 
 func (as AccountServer) AuthenticateByEmail(ctx context.Context, r *account_service.AuthenticateByEmailRequest) (*account_service.Account, error) {
-	a, err := as.DB.ReadByEmail(r.Email)
+	a, err := as.checkPassword(r.Email, r.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	if a == nil {
+	m, err := as.enabledMfa(a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		err = as.loggedIn(a)
+		if err != nil {
+			return nil, err
+		}
+
+		return accountDetailsFromAccount(a), nil
+	}
+
+	// the login is completed by VerifyMfa, which also clears any failures
+	// and records it
+	return nil, as.mfaChallengeError(m)
+}
+
+// authenticate returns the account with the given email and password, when
+// the account has MFA enabled code must be a valid TOTP or recovery code
+func (as AccountServer) authenticate(email, password, code string) (*database.Account, error) {
+	a, err := as.checkPassword(email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := as.enabledMfa(a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m != nil && code == "" {
+		return nil, ErrMfaRequired
+	}
+
+	if m != nil {
+		err = as.verifyMfaCode(m, code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = as.loggedIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// checkCanLogIn refuses logins to a while the account or its email is
+// locked out, and with RequireConfirmation until it's confirmed. Every way
+// of logging in checks it once the credential it was given is known to be
+// right, before completing the login with loggedIn.
+func (as AccountServer) checkCanLogIn(a *database.Account) error {
+	err := as.checkLocked(
+		database.AccountSubject(a.ID),
+		database.EmailSubject(a.Email),
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkConfirmed(a)
+}
+
+// loggedIn completes a login to a, clearing the failures counted for it and
+// recording when it happened. Failing to record it isn't a reason to refuse
+// the login.
+func (as AccountServer) loggedIn(a *database.Account) error {
+	err := as.clearLoginFailures(a)
+	if err != nil {
+		return err
+	}
+
+	err = as.DB.RecordLogin(a)
+	if err != nil {
+		logrus.Errorf("login record error for account %s: %v", a.ID, err)
+	}
+
+	return nil
+}
+
+// checkPassword returns the account with the given email and password.
+// Failures are counted against both the email and the account, logins are
+// refused while either is locked out, before the password is compared. With
+// EnumerationSafe an unknown email fails like a wrong password, taking as
+// long to do so. Accounts checkCanLogIn refuses are refused.
+func (as AccountServer) checkPassword(email, password string) (*database.Account, error) {
+	emailSubject := database.EmailSubject(email)
+	err := as.checkLocked(emailSubject)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := as.DB.ReadByEmail(email)
+	if err == database.ErrAccountNotFound {
+		database.CompareDummyPassword(password)
+		err = as.loginFailed(emailSubject)
+		if err != nil {
+			return nil, err
+		}
+
+		if EnumerationSafe {
+			return nil, ErrLoginIncorrect
+		}
+
 		return nil, grpc.Errorf(codes.NotFound, "account not found")
 	}
 
-	err = a.ComparePasswordToHash(r.Password)
 	if err != nil {
+		logrus.Errorf("authentication error %v", err)
+		return nil, ErrInternal
+	}
+
+	accountSubject := database.AccountSubject(a.ID)
+	err = as.checkLocked(accountSubject)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.ComparePasswordToHash(password)
+	if err != nil && err != database.ErrPasswordMismatch {
+		// e.g a pepper dropped too soon, the password may well be right
+		logrus.Errorf("password compare error for account %s: %v", a.ID, err)
+		return nil, ErrInternal
+	}
+
+	if err != nil {
+		err = as.loginFailed(emailSubject, accountSubject)
+		if err != nil {
+			return nil, err
+		}
+
+		if EnumerationSafe {
+			return nil, ErrLoginIncorrect
+		}
+
 		return nil, grpc.Errorf(codes.PermissionDenied, "password incorrect")
 	}
 
-	return accountDetailsFromAccount(a), nil
+	if a.PasswordOutdated() {
+		as.rehashPassword(a, password)
+	}
+
+	// only checked once the password is known to be right, so it doesn't
+	// give away whether an account is confirmed
+	err = as.checkCanLogIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// rehashPassword hashes the password of a again with the current algorithm
+// and parameters. Failing to isn't a reason to refuse the login, it's tried
+// again at the next one.
+func (as AccountServer) rehashPassword(a *database.Account, password string) {
+	// the version check stops a password changed since a was read being
+	// overwritten
+	u := database.Account{ID: a.ID, Version: a.Version}
+	err := u.HashPassword(password)
+	if err == nil {
+		err = as.DB.Update(&u, "hashed_password")
+	}
+
+	if err != nil {
+		logrus.Errorf("password rehash error %v", err)
+		return
+	}
+
+	a.HashedPassword = u.HashedPassword
+	a.Version = u.Version
 }
@@ -1,12 +1,15 @@
 package server
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
 )
@@ -26,6 +29,12 @@ func TestAuthenticate(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotEmpty(t, a.Id)
 	assert.NotEmpty(t, a.Email)
+	assert.NotNil(t, a.LastLoginAt)
+	assertNoSecrets(t, a)
+
+	ra, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, a.LastLoginAt, ra.LastLoginAt)
 }
 
 func TestAuthenticateFailure(t *testing.T) {
@@ -43,3 +52,218 @@ func TestAuthenticateFailure(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, grpc.Code(err), codes.PermissionDenied)
 }
+
+func TestAuthenticateNotFound(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+
+	ar := &account_service.AuthenticateByEmailRequest{
+		Email:    "nobody@localhost",
+		Password: pass,
+	}
+
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrLoginIncorrect, err)
+}
+
+func TestAuthenticateUnknownEmailLikeWrongPassword(t *testing.T) {
+	truncate()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	wrong := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "wrong"}
+	_, wrongErr := as.AuthenticateByEmail(ctx, wrong)
+
+	unknown := &account_service.AuthenticateByEmailRequest{Email: "nobody@localhost", Password: "wrong"}
+	_, unknownErr := as.AuthenticateByEmail(ctx, unknown)
+
+	assert.NotNil(t, unknownErr)
+	assert.Equal(t, grpc.Code(wrongErr), grpc.Code(unknownErr))
+	assert.Equal(t, grpc.ErrorDesc(wrongErr), grpc.ErrorDesc(unknownErr))
+}
+
+func TestAuthenticateNotFoundUnsafe(t *testing.T) {
+	defer withEnumerationSafe(false)()
+	truncate()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: "nobody@localhost", Password: pass}
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+
+	ar = &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "wrong"}
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+	assert.NotEqual(t, ErrLoginIncorrect, err)
+}
+
+func TestAuthenticateMfaChallenge(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	enableMfa(t, a)
+
+	ar := &account_service.AuthenticateByEmailRequest{
+		Email:    a.Email,
+		Password: pass,
+	}
+
+	res, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, res)
+	assertMfaChallenge(t, err)
+}
+
+func TestAuthenticateLockout(t *testing.T) {
+	defer withLockoutThreshold(3)()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	wrong := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "incorrect password lol"}
+	for i := 0; i < 3; i++ {
+		_, err := as.AuthenticateByEmail(ctx, wrong)
+		assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+	}
+
+	// the right password is refused too while locked
+	right := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err := as.AuthenticateByEmail(ctx, right)
+	assertLocked(t, err)
+
+	// as are other ways of logging in
+	_, err = as.CreateSession(ctx, &account_service.CreateSessionRequest{Email: a.Email, Password: pass})
+	assertLocked(t, err)
+}
+
+func TestAuthenticateSuccessResetsFailures(t *testing.T) {
+	defer withLockoutThreshold(3)()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	wrong := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "incorrect password lol"}
+	right := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+
+	for i := 0; i < 3; i++ {
+		_, err := as.AuthenticateByEmail(ctx, wrong)
+		assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+
+		if i%2 == 1 {
+			_, err = as.AuthenticateByEmail(ctx, right)
+			assert.Nil(t, err)
+		}
+	}
+
+	_, err := as.AuthenticateByEmail(ctx, right)
+	assert.Nil(t, err)
+}
+
+func TestAuthenticateLockoutUnknownEmail(t *testing.T) {
+	defer withLockoutThreshold(2)()
+	truncate()
+	ctx := context.Background()
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: "nobody@localhost", Password: pass}
+	for i := 0; i < 2; i++ {
+		_, err := as.AuthenticateByEmail(ctx, ar)
+		assert.Equal(t, ErrLoginIncorrect, err)
+	}
+
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assertLocked(t, err)
+}
+
+func TestAuthenticateRehashesOutdatedPassword(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	previous := database.PasswordHasher
+	database.PasswordHasher = &database.Argon2id{Memory: 1024, Time: 1, Threads: 1}
+	defer func() { database.PasswordHasher = previous }()
+
+	before, err := as.DB.ReadByID(a.Id)
+	assert.Nil(t, err)
+	assert.True(t, before.PasswordOutdated())
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+
+	after, err := as.DB.ReadByID(a.Id)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(after.HashedPassword, "$argon2id$"), after.HashedPassword)
+	assert.False(t, after.PasswordOutdated())
+
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
+
+func TestAuthenticateRepeppersPassword(t *testing.T) {
+	ctx := context.Background()
+
+	previous := database.Peppers
+	defer func() { database.Peppers = previous }()
+
+	old := database.NewPepper("old", []byte("0123456789abcdef"))
+	database.Peppers = []*database.Pepper{old}
+	a := createAccount(t)
+
+	database.Peppers = []*database.Pepper{database.NewPepper("new", []byte("fedcba9876543210")), old}
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+
+	ra, err := as.DB.ReadByID(a.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", ra.PepperID)
+
+	// the old pepper can be dropped once everyone has logged in
+	database.Peppers = database.Peppers[:1]
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
+
+func TestAuthenticateRequiresConfirmation(t *testing.T) {
+	defer withRequireConfirmation(true, 0)()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.AuthenticateByEmailRequest{
+		Email:    a.Email,
+		Password: pass,
+	}
+
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assertUnconfirmed(t, err, a.Email)
+
+	// a wrong password doesn't give away that the account is unconfirmed
+	_, err = as.AuthenticateByEmail(ctx, &account_service.AuthenticateByEmailRequest{
+		Email:    a.Email,
+		Password: "incorrect password lol",
+	})
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+
+	_, err = as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: a.ConfirmToken})
+	assert.Nil(t, err)
+
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
+
+func TestAuthenticateConfirmationGracePeriod(t *testing.T) {
+	defer withRequireConfirmation(true, time.Hour)()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.AuthenticateByEmailRequest{
+		Email:    a.Email,
+		Password: pass,
+	}
+
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+
+	ConfirmationGracePeriod = time.Nanosecond
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assertUnconfirmed(t, err, a.Email)
+}
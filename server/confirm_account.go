@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -10,7 +11,13 @@ import (
 func (as AccountServer) ConfirmAccount(ctx context.Context, r *account_service.ConfirmAccountRequest) (*account_service.Account, error) {
 	a, err := as.DB.Confirm(r.Token)
 	if err != nil {
-		return nil, grpc.Errorf(codes.InvalidArgument, "token incorrect %s", err)
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "confirmation token not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "confirmation token expired")
+		}
+		return nil, err
 	}
 
 	return accountDetailsFromAccount(a), nil
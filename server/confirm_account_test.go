@@ -2,10 +2,14 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestConfirmAccount(t *testing.T) {
@@ -18,4 +22,27 @@ func TestConfirmAccount(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Empty(t, res.ConfirmToken)
 	assert.NotNil(t, res)
+	assert.True(t, res.Confirmed)
+	assert.NotNil(t, res.ConfirmedAt)
+}
+
+func TestConfirmAccountNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	req := &account_service.ConfirmAccountRequest{Token: "unknown"}
+	_, err := as.ConfirmAccount(ctx, req)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestConfirmAccountExpired(t *testing.T) {
+	ctx := context.Background()
+
+	ttl := database.ConfirmationTTL
+	database.ConfirmationTTL = -time.Minute
+	defer func() { database.ConfirmationTTL = ttl }()
+
+	ac := createAccount(t)
+	req := &account_service.ConfirmAccountRequest{Token: ac.ConfirmToken}
+	_, err := as.ConfirmAccount(ctx, req)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
 }
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) ConfirmEmailChange(ctx context.Context, r *account_service.ConfirmEmailChangeRequest) (*account_service.EmailChange, error) {
+	a, err := as.DB.ConfirmEmailChange(r.Token)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "email change not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "email change token expired")
+		}
+		if err == database.ErrEmailExists {
+			return nil, grpc.Errorf(codes.AlreadyExists, err.Error())
+		}
+		return nil, err
+	}
+
+	return &account_service.EmailChange{
+		AccountId: a.ID,
+		OldEmail:  a.PreviousEmail,
+		NewEmail:  a.Email,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	c := requestEmailChange(t, a)
+
+	req := &account_service.ConfirmEmailChangeRequest{Token: c.Token}
+	res, err := as.ConfirmEmailChange(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, res.AccountId)
+	assert.Equal(t, a.Email, res.OldEmail)
+	assert.Equal(t, c.NewEmail, res.NewEmail)
+	assert.Empty(t, res.Token)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: c.NewEmail, Password: pass}
+	acc, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+
+	// the token can't be used again
+	_, err = as.ConfirmEmailChange(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestConfirmEmailChangeExpired(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ttl := database.EmailChangeTTL
+	database.EmailChangeTTL = -time.Minute
+	defer func() { database.EmailChangeTTL = ttl }()
+
+	c := requestEmailChange(t, a)
+	_, err := as.ConfirmEmailChange(ctx, &account_service.ConfirmEmailChangeRequest{Token: c.Token})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
+
+func TestConfirmEmailChangeTaken(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	c := requestEmailChange(t, a)
+
+	// another account takes the email in the meantime
+	cr := &account_service.CreateAccountRequest{
+		Account:  &account_service.Account{Name: name, Email: c.NewEmail},
+		Password: pass,
+	}
+	_, err := as.Create(ctx, cr)
+	assert.Nil(t, err)
+
+	_, err = as.ConfirmEmailChange(ctx, &account_service.ConfirmEmailChangeRequest{Token: c.Token})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.AlreadyExists, grpc.Code(err))
+}
+
+func TestConfirmEmailChangeNotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := as.ConfirmEmailChange(ctx, &account_service.ConfirmEmailChangeRequest{Token: "unknown"})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"time"
+
+	"github.com/lileio/account_service/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ViolationUnconfirmed is the type of the google.rpc.PreconditionFailure
+// violation a login to an unconfirmed account fails with, its subject is
+// the email to resend the confirmation to
+const ViolationUnconfirmed = "UNCONFIRMED"
+
+const unconfirmedMessage = "account must be confirmed before logging in"
+
+// checkConfirmed fails with FailedPrecondition when RequireConfirmation
+// refuses logins to a until it's confirmed
+func checkConfirmed(a *database.Account) error {
+	if !RequireConfirmation || a.Confirmed() {
+		return nil
+	}
+
+	if time.Since(a.CreatedAt) < ConfirmationGracePeriod {
+		return nil
+	}
+
+	st, err := status.New(codes.FailedPrecondition, unconfirmedMessage).
+		WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        ViolationUnconfirmed,
+				Subject:     a.Email,
+				Description: unconfirmedMessage,
+			}},
+		})
+	if err != nil {
+		return grpc.Errorf(codes.FailedPrecondition, unconfirmedMessage)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) ConsumeLoginLink(ctx context.Context, r *account_service.ConsumeLoginLinkRequest) (*account_service.ConsumeLoginLinkResponse, error) {
+	l, err := as.DB.ConsumeLoginLink(r.Token)
+	if err != nil {
+		if err == database.ErrNoLoginLink {
+			return nil, grpc.Errorf(codes.NotFound, "login link not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "login link expired")
+		}
+		return nil, err
+	}
+
+	a, err := as.DB.ReadByID(l.AccountID)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, err
+	}
+
+	err = as.checkCanLogIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := as.enabledMfa(a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the link stands in for the password, not the second factor
+	if m != nil {
+		return nil, as.mfaChallengeError(m)
+	}
+
+	err = as.loggedIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	s := database.Session{
+		AccountID: a.ID,
+		UserAgent: r.UserAgent,
+		Device:    r.Device,
+	}
+
+	err = as.DB.CreateSession(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account_service.ConsumeLoginLinkResponse{
+		Account: accountDetailsFromAccount(a),
+		Session: sessionDetailsFromSession(&s),
+	}, nil
+}
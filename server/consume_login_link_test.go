@@ -0,0 +1,117 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func loginLink(t *testing.T, a *account_service.Account) string {
+	req := &account_service.RequestLoginLinkRequest{Email: a.Email}
+	res, err := as.RequestLoginLink(context.Background(), req)
+	assert.Nil(t, err)
+	return res.Token
+}
+
+func TestConsumeLoginLink(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.ConsumeLoginLinkRequest{
+		Token:     loginLink(t, a),
+		UserAgent: "curl/7.54",
+		Device:    "laptop",
+	}
+
+	res, err := as.ConsumeLoginLink(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, res.Account.Id)
+	assertNoSecrets(t, res.Account)
+	assert.Equal(t, a.Id, res.Session.AccountId)
+	assert.NotEmpty(t, res.Session.AccessToken)
+	assert.NotEmpty(t, res.Session.RefreshToken)
+	assert.Equal(t, "laptop", res.Session.Device)
+
+	// the link can't be used again
+	_, err = as.ConsumeLoginLink(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestConsumeLoginLinkNotFound(t *testing.T) {
+	ctx := context.Background()
+	req := &account_service.ConsumeLoginLinkRequest{Token: "unknown"}
+	_, err := as.ConsumeLoginLink(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestConsumeLoginLinkExpired(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ttl := database.LoginLinkTTL
+	database.LoginLinkTTL = -time.Minute
+	defer func() { database.LoginLinkTTL = ttl }()
+
+	req := &account_service.ConsumeLoginLinkRequest{Token: loginLink(t, a)}
+	_, err := as.ConsumeLoginLink(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
+
+func TestConsumeLoginLinkMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+
+	req := &account_service.ConsumeLoginLinkRequest{Token: loginLink(t, a)}
+	res, err := as.ConsumeLoginLink(ctx, req)
+	assert.Nil(t, res)
+	c := assertMfaChallenge(t, err)
+
+	vr := &account_service.VerifyMfaRequest{
+		Challenge: c.Token,
+		Code:      mfaCode(t, secret, 0),
+	}
+
+	acc, err := as.VerifyMfa(ctx, vr)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+}
+
+func TestConsumeLoginLinkLocked(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	defer withLockoutThreshold(2)()
+
+	for i := 0; i < 2; i++ {
+		ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "wrong"}
+		_, err := as.AuthenticateByEmail(ctx, ar)
+		assert.NotNil(t, err)
+	}
+
+	req := &account_service.ConsumeLoginLinkRequest{Token: loginLink(t, a)}
+	_, err := as.ConsumeLoginLink(ctx, req)
+	assertLocked(t, err)
+
+	sessions, err := as.ListSessions(ctx, &account_service.ListSessionsRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+	assert.Empty(t, sessions.Sessions)
+}
+
+func TestConsumeLoginLinkUnconfirmed(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	defer withRequireConfirmation(true, 0)()
+
+	req := &account_service.ConsumeLoginLinkRequest{Token: loginLink(t, a)}
+	_, err := as.ConsumeLoginLink(ctx, req)
+	assertUnconfirmed(t, err, a.Email)
+}
@@ -25,6 +25,11 @@ func (as AccountServer) Create(ctx context.Context, r *account_service.CreateAcc
 		return nil, err
 	}
 
+	err = validatePassword(r.Password, &a)
+	if err != nil {
+		return nil, err
+	}
+
 	err = database.EmailExists(as.DB, &a)
 	if err != nil {
 		return nil, grpc.Errorf(codes.AlreadyExists, err.Error())
@@ -48,5 +53,7 @@ func (as AccountServer) Create(ctx context.Context, r *account_service.CreateAcc
 		return nil, err
 	}
 
-	return accountDetailsFromAccount(&a), nil
+	acc := accountDetailsFromAccount(&a)
+	acc.ConfirmToken = a.ConfirmationToken
+	return acc, nil
 }
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) CreateSession(ctx context.Context, r *account_service.CreateSessionRequest) (*account_service.Session, error) {
+	a, err := as.authenticate(r.Email, r.Password, r.MfaCode)
+	if err != nil {
+		return nil, err
+	}
+
+	s := database.Session{
+		AccountID: a.ID,
+		UserAgent: r.UserAgent,
+		Device:    r.Device,
+	}
+
+	err = as.DB.CreateSession(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionDetailsFromSession(&s), nil
+}
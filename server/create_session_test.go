@@ -0,0 +1,73 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateSession(t *testing.T) {
+	a := createAccount(t)
+	s := createSession(t, a)
+	assert.NotEmpty(t, s.Id)
+	assert.Equal(t, a.Id, s.AccountId)
+	assert.NotEmpty(t, s.AccessToken)
+	assert.NotEmpty(t, s.RefreshToken)
+	assert.NotNil(t, s.AccessTokenExpiresAt)
+	assert.NotNil(t, s.RefreshTokenExpiresAt)
+	assert.Equal(t, "curl/7.54", s.UserAgent)
+	assert.Equal(t, "laptop", s.Device)
+}
+
+func TestCreateSessionWrongPassword(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.CreateSessionRequest{
+		Email:    a.Email,
+		Password: "incorrect password lol",
+	}
+
+	_, err := as.CreateSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestCreateSessionNotFound(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+
+	req := &account_service.CreateSessionRequest{
+		Email:    "nobody@localhost",
+		Password: pass,
+	}
+
+	_, err := as.CreateSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrLoginIncorrect, err)
+}
+
+func TestCreateSessionMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+
+	req := &account_service.CreateSessionRequest{
+		Email:    a.Email,
+		Password: pass,
+	}
+
+	_, err := as.CreateSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+
+	req.MfaCode = mfaCode(t, secret, 0)
+	s, err := as.CreateSession(ctx, req)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, s.RefreshToken)
+}
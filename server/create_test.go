@@ -17,6 +17,11 @@ func TestCreateSuccess(t *testing.T) {
 
 	account := createAccount(t)
 	assert.NotEmpty(t, account.Id)
+	assert.False(t, account.Confirmed)
+	assert.NotNil(t, account.CreatedAt)
+	assert.Equal(t, account.CreatedAt, account.UpdatedAt)
+	assert.Nil(t, account.ConfirmedAt)
+	assert.Nil(t, account.LastLoginAt)
 }
 
 func BenchmarkCreate(b *testing.B) {
@@ -67,3 +72,25 @@ func TestCreateEmpty(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, account)
 }
+
+func TestCreatePasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	req := &account_service.CreateAccountRequest{
+		Account: &account_service.Account{
+			Name:  name,
+			Email: "policy@localhost",
+		},
+		Password: "alex",
+	}
+
+	_, err := as.Create(ctx, req)
+	v := assertPasswordViolations(t, err)
+	assert.Equal(t, []string{
+		"must be at least 8 characters",
+		"must not contain your email or name",
+	}, v)
+
+	_, err = as.GetByEmail(ctx, &account_service.GetByEmailRequest{Email: "policy@localhost"})
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -18,6 +18,10 @@ func (as AccountServer) Delete(ctx context.Context, r *account_service.DeleteAcc
 		return nil, err
 	}
 
+	if r.Version != 0 && r.Version != ca.Version {
+		return nil, ErrVersionConflict
+	}
+
 	err = as.deleteImages(ctx, ca)
 	if err != nil {
 		return nil, err
@@ -29,8 +33,11 @@ func (as AccountServer) Delete(ctx context.Context, r *account_service.DeleteAcc
 		return nil, err
 	}
 
-	err = as.DB.Delete(r.Id)
+	err = as.DB.Delete(r.Id, r.Version)
 	if err != nil {
+		if err == database.ErrVersionConflict {
+			return nil, ErrVersionConflict
+		}
 		return nil, err
 	}
 
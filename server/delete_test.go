@@ -7,6 +7,8 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestDeleteSuccess(t *testing.T) {
@@ -31,3 +33,18 @@ func TestDeleteAccountNotExist(t *testing.T) {
 	_, err := as.Delete(ctx, dr)
 	assert.NotNil(t, err)
 }
+
+func TestDeleteVersionConflict(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	dr := &account_service.DeleteAccountRequest{Id: a.Id, Version: a.Version + 1}
+	_, err := as.Delete(ctx, dr)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.Aborted)
+
+	_, err = as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) DisableMfa(ctx context.Context, r *account_service.DisableMfaRequest) (*empty.Empty, error) {
+	err := as.DB.DisableMfa(r.AccountId)
+	if err != nil {
+		if err == database.ErrMfaNotFound {
+			return nil, ErrMfaNotEnrolled
+		}
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestDisableMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	enableMfa(t, a)
+
+	_, err := as.DisableMfa(ctx, &account_service.DisableMfaRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	acc, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+
+	_, err = as.DisableMfa(ctx, &account_service.DisableMfaRequest{AccountId: a.Id})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/totp"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) EnrolMfa(ctx context.Context, r *account_service.EnrolMfaRequest) (*account_service.EnrolMfaResponse, error) {
+	a, err := as.DB.ReadByID(r.AccountId)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.DB.EnrolMfa(a.ID, secret)
+	if err != nil {
+		if err == database.ErrMfaEnabled {
+			return nil, ErrMfaEnabled
+		}
+		return nil, err
+	}
+
+	return &account_service.EnrolMfaResponse{
+		Secret: secret,
+		Uri:    totp.URI(a.Email, secret),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestEnrolMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	res, err := as.EnrolMfa(ctx, &account_service.EnrolMfaRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Secret)
+	assert.True(t, strings.HasPrefix(res.Uri, "otpauth://totp/"))
+	assert.Contains(t, res.Uri, a.Email)
+	assert.Contains(t, res.Uri, "secret="+res.Secret)
+
+	// MFA isn't required until it's activated
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	acc, err := as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+}
+
+func TestEnrolMfaEnabled(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	enableMfa(t, a)
+
+	_, err := as.EnrolMfa(ctx, &account_service.EnrolMfaRequest{AccountId: a.Id})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
+
+func TestEnrolMfaNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := as.EnrolMfa(ctx, &account_service.EnrolMfaRequest{AccountId: "missing"})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
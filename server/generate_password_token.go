@@ -1,6 +1,7 @@
 package server
 
 import (
+	"github.com/golang/protobuf/ptypes"
 	"github.com/lileio/account_service"
 	"github.com/lileio/account_service/database"
 	context "golang.org/x/net/context"
@@ -11,13 +12,28 @@ import (
 func (as AccountServer) GeneratePasswordToken(ctx context.Context, r *account_service.GeneratePasswordTokenRequest) (*account_service.GeneratePasswordTokenResponse, error) {
 	a, err := as.DB.GeneratePasswordToken(r.Email)
 	if err != nil {
+		// there's no token to send, succeeding lets callers show users the
+		// same message whether or not the email is registered. Unknown
+		// emails return sooner as nothing is written, and the response has
+		// no token, so only trusted callers that send the token on without
+		// passing either back to the user should call this.
+		if err == database.ErrAccountNotFound && EnumerationSafe {
+			return &account_service.GeneratePasswordTokenResponse{}, nil
+		}
+
 		if err == database.ErrAccountNotFound {
 			return nil, grpc.Errorf(codes.NotFound, "account not found")
 		}
 		return nil, err
 	}
 
+	expires, err := ptypes.TimestampProto(a.PasswordResetExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
 	return &account_service.GeneratePasswordTokenResponse{
-		Token: a.PasswordResetToken,
+		Token:     a.PasswordResetToken,
+		ExpiresAt: expires,
 	}, nil
 }
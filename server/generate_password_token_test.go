@@ -2,10 +2,14 @@ package server
 
 import (
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/lileio/account_service"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestGeneratePasswordToken(t *testing.T) {
@@ -17,4 +21,28 @@ func TestGeneratePasswordToken(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, res)
 	assert.NotEmpty(t, res.Token)
+	assert.NotNil(t, res.ExpiresAt)
+
+	expires, err := ptypes.Timestamp(res.ExpiresAt)
+	assert.Nil(t, err)
+	assert.True(t, expires.After(time.Now()))
+}
+
+func TestGeneratePasswordTokenUnknownEmail(t *testing.T) {
+	ctx := context.Background()
+
+	req := &account_service.GeneratePasswordTokenRequest{Email: "nobody@localhost"}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.Empty(t, res.Token)
+}
+
+func TestGeneratePasswordTokenUnknownEmailUnsafe(t *testing.T) {
+	defer withEnumerationSafe(false)()
+	ctx := context.Background()
+
+	req := &account_service.GeneratePasswordTokenRequest{Email: "nobody@localhost"}
+	_, err := as.GeneratePasswordToken(ctx, req)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
 }
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) GenerateRecoveryCodes(ctx context.Context, r *account_service.GenerateRecoveryCodesRequest) (*account_service.GenerateRecoveryCodesResponse, error) {
+	m, err := as.enabledMfa(r.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		return nil, ErrMfaNotEnabled
+	}
+
+	codes, err := generateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = hashRecoveryCode(c)
+	}
+
+	// replaces any codes generated before
+	err = as.DB.SetRecoveryCodes(m.AccountID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account_service.GenerateRecoveryCodesResponse{Codes: codes}, nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	enableMfa(t, a)
+
+	req := &account_service.GenerateRecoveryCodesRequest{AccountId: a.Id}
+	res, err := as.GenerateRecoveryCodes(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, RecoveryCodeCount, len(res.Codes))
+
+	seen := map[string]bool{}
+	for _, c := range res.Codes {
+		assert.Len(t, c, 9)
+		assert.False(t, seen[c])
+		seen[c] = true
+	}
+}
+
+func TestGenerateRecoveryCodesNotEnabled(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.GenerateRecoveryCodesRequest{AccountId: a.Id}
+	_, err := as.GenerateRecoveryCodes(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
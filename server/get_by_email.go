@@ -17,5 +17,5 @@ func (as AccountServer) GetByEmail(ctx context.Context, r *account_service.GetBy
 		return nil, err
 	}
 
-	return accountDetailsFromAccount(a), nil
+	return accountView(a, r.View), nil
 }
@@ -23,3 +23,20 @@ func TestGetByEmailSuccess(t *testing.T) {
 	assert.NotEmpty(t, a2.Id)
 	assert.NotEmpty(t, a2.Email)
 }
+
+func TestGetByEmailNoSecrets(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccountWithTokens(t)
+
+	for _, view := range []account_service.AccountView{
+		account_service.AccountView_FULL,
+		account_service.AccountView_BASIC,
+	} {
+		a2, err := as.GetByEmail(ctx, &account_service.GetByEmailRequest{Email: a.Email, View: view})
+		assert.Nil(t, err)
+		assert.Equal(t, a2.Id, a.Id)
+		assertNoSecrets(t, a2)
+	}
+}
@@ -18,5 +18,5 @@ func (as AccountServer) GetById(ctx context.Context, r *account_service.GetByIdR
 		return nil, err
 	}
 
-	return accountDetailsFromAccount(a), nil
+	return accountView(a, r.View), nil
 }
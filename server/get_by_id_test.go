@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
@@ -43,3 +44,49 @@ func TestGetByIdFail(t *testing.T) {
 	assert.Equal(t, grpc.Code(err), codes.NotFound)
 	assert.Nil(t, a2)
 }
+
+func TestGetByIdNoSecrets(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccountWithTokens(t)
+
+	for _, view := range []account_service.AccountView{
+		account_service.AccountView_FULL,
+		account_service.AccountView_BASIC,
+	} {
+		a2, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id, View: view})
+		assert.Nil(t, err)
+		assert.Equal(t, a2.Email, a.Email)
+		assertNoSecrets(t, a2)
+	}
+}
+
+func TestGetByIdViews(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccountWithTokens(t)
+
+	a2, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, a2.Metadata, a.Metadata)
+
+	a2, err = as.GetById(ctx, &account_service.GetByIdRequest{
+		Id:   a.Id,
+		View: account_service.AccountView_BASIC,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, a2.Name, a.Name)
+	assert.Empty(t, a2.Metadata)
+
+	// the admin view shows token digests, never the tokens themselves
+	a2, err = as.GetById(ctx, &account_service.GetByIdRequest{
+		Id:   a.Id,
+		View: account_service.AccountView_ADMIN,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, a2.ConfirmTokenHash, database.HashToken(a.ConfirmToken))
+	assert.Equal(t, a2.PasswordResetTokenHash, database.HashToken(a.PasswordResetToken))
+	assertNoSecrets(t, a2)
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) GetPublicKeys(ctx context.Context, r *account_service.GetPublicKeysRequest) (*account_service.GetPublicKeysResponse, error) {
+	res := &account_service.GetPublicKeysResponse{
+		Keys: []*account_service.PublicKey{},
+	}
+
+	if as.Keys == nil {
+		return res, nil
+	}
+
+	for _, k := range as.Keys.PublicKeys() {
+		res.Keys = append(res.Keys, &account_service.PublicKey{
+			Kty: k.KeyType,
+			Kid: k.KeyID,
+			Use: k.Use,
+			Alg: k.Algorithm,
+			N:   k.N,
+			E:   k.E,
+			Crv: k.Curve,
+			X:   k.X,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+)
+
+func TestGetPublicKeys(t *testing.T) {
+	setupKeys(t)
+	ctx := context.Background()
+
+	res, err := as.GetPublicKeys(ctx, &account_service.GetPublicKeysRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res.Keys))
+
+	k := res.Keys[0]
+	assert.NotEmpty(t, k.Kid)
+	assert.Equal(t, "OKP", k.Kty)
+	assert.Equal(t, "Ed25519", k.Crv)
+	assert.Equal(t, "EdDSA", k.Alg)
+	assert.Equal(t, "sig", k.Use)
+	assert.NotEmpty(t, k.X)
+	assert.Empty(t, k.N)
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"time"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/jwt"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) IssueToken(ctx context.Context, r *account_service.IssueTokenRequest) (*account_service.IssueTokenResponse, error) {
+	if as.Keys == nil {
+		return nil, ErrNoSigningKeys
+	}
+
+	a, err := as.authenticate(r.Email, r.Password, r.MfaCode)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.NewClaims(a.ID, a.Email, a.Confirmed())
+	t, err := as.Keys.Sign(claims)
+	if err == jwt.ErrNoKeys {
+		return nil, ErrNoSigningKeys
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &account_service.IssueTokenResponse{
+		Token:     t,
+		ExpiresAt: timestampProto(time.Unix(claims.ExpiresAt, 0)),
+	}, nil
+}
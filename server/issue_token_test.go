@@ -0,0 +1,72 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestIssueToken(t *testing.T) {
+	setupKeys(t)
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.IssueTokenRequest{Email: a.Email, Password: pass}
+	res, err := as.IssueToken(ctx, req)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Token)
+	assert.NotNil(t, res.ExpiresAt)
+
+	c, err := as.Keys.Verify(res.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, c.Subject)
+	assert.Equal(t, a.Email, c.Email)
+	assert.False(t, c.EmailVerified)
+	assert.Equal(t, res.ExpiresAt.Seconds, c.ExpiresAt)
+}
+
+func TestIssueTokenConfirmed(t *testing.T) {
+	setupKeys(t)
+	ctx := context.Background()
+	a := createAccount(t)
+
+	_, err := as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: a.ConfirmToken})
+	assert.Nil(t, err)
+
+	req := &account_service.IssueTokenRequest{Email: a.Email, Password: pass}
+	res, err := as.IssueToken(ctx, req)
+	assert.Nil(t, err)
+
+	c, err := as.Keys.Verify(res.Token)
+	assert.Nil(t, err)
+	assert.True(t, c.EmailVerified)
+}
+
+func TestIssueTokenWrongPassword(t *testing.T) {
+	setupKeys(t)
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.IssueTokenRequest{Email: a.Email, Password: "incorrect password lol"}
+	_, err := as.IssueToken(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestIssueTokenNotConfigured(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	keys := as.Keys
+	as.Keys = nil
+	defer func() { as.Keys = keys }()
+
+	req := &account_service.IssueTokenRequest{Email: a.Email, Password: pass}
+	_, err := as.IssueToken(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
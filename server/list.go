@@ -1,22 +1,34 @@
 package server
 
 import (
+	"github.com/golang/protobuf/ptypes"
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func (as AccountServer) List(
 	ctx context.Context, l *account_service.ListAccountsRequest) (
 	*account_service.ListAccountsResponse, error) {
 
-	accounts, next_token, err := as.DB.List(l.PageSize, l.PageToken)
+	opts, err := listOptions(l)
 	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	accounts, next_token, err := as.DB.List(l.PageSize, l.PageToken, opts)
+	if err != nil {
+		if err == database.ErrInvalidPageToken || err == database.ErrInvalidOrderBy {
+			return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+		}
 		return nil, err
 	}
 
 	accs := make([]*account_service.Account, len(accounts))
 	for i, acc := range accounts {
-		accs[i] = accountDetailsFromAccount(acc)
+		accs[i] = accountView(acc, l.View)
 	}
 
 	return &account_service.ListAccountsResponse{
@@ -24,3 +36,39 @@ func (as AccountServer) List(
 		NextPageToken: next_token,
 	}, err
 }
+
+func listOptions(l *account_service.ListAccountsRequest) (database.ListOptions, error) {
+	opts := database.ListOptions{
+		EmailPrefix:  l.EmailPrefix,
+		NameContains: l.NameContains,
+		Metadata:     l.Metadata,
+		OrderBy:      l.OrderBy,
+	}
+
+	if l.CreatedAfter != nil {
+		t, err := ptypes.Timestamp(l.CreatedAfter)
+		if err != nil {
+			return opts, err
+		}
+		opts.CreatedAfter = t
+	}
+
+	if l.CreatedBefore != nil {
+		t, err := ptypes.Timestamp(l.CreatedBefore)
+		if err != nil {
+			return opts, err
+		}
+		opts.CreatedBefore = t
+	}
+
+	switch l.Confirmed {
+	case account_service.ConfirmedFilter_CONFIRMED_FILTER_CONFIRMED:
+		confirmed := true
+		opts.Confirmed = &confirmed
+	case account_service.ConfirmedFilter_CONFIRMED_FILTER_UNCONFIRMED:
+		confirmed := false
+		opts.Confirmed = &confirmed
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) ListSessions(ctx context.Context, r *account_service.ListSessionsRequest) (*account_service.ListSessionsResponse, error) {
+	if r.AccountId == "" {
+		return nil, ErrNoAccountID
+	}
+
+	sessions, err := as.DB.ListSessions(r.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &account_service.ListSessionsResponse{
+		Sessions: make([]*account_service.Session, len(sessions)),
+	}
+
+	for i, s := range sessions {
+		res.Sessions[i] = sessionDetailsFromSession(s)
+	}
+
+	return res, nil
+}
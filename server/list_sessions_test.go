@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestListSessions(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	s := createSession(t, a)
+	createSession(t, createAccount(t))
+
+	res, err := as.ListSessions(ctx, &account_service.ListSessionsRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res.Sessions))
+	assert.Equal(t, s.Id, res.Sessions[0].Id)
+	assert.Equal(t, "laptop", res.Sessions[0].Device)
+	assert.NotNil(t, res.Sessions[0].LastSeenAt)
+	assert.Empty(t, res.Sessions[0].AccessToken)
+	assert.Empty(t, res.Sessions[0].RefreshToken)
+}
+
+func TestListSessionsNoAccountID(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := as.ListSessions(ctx, &account_service.ListSessionsRequest{})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"testing"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
 	"github.com/lileio/account_service"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
@@ -56,3 +59,75 @@ func TestSimpleListToken(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Empty(t, l.NextPageToken)
 }
+
+func TestListInvalidToken(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	req := &account_service.ListAccountsRequest{
+		PageSize:  2,
+		PageToken: "notatoken",
+	}
+
+	_, err := as.List(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.InvalidArgument)
+}
+
+func TestListFilter(t *testing.T) {
+	truncate()
+
+	for i := 0; i < 3; i++ {
+		createAccount(t)
+	}
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	_, err := as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: a.ConfirmToken})
+	assert.Nil(t, err)
+
+	req := &account_service.ListAccountsRequest{
+		PageSize:  10,
+		Confirmed: account_service.ConfirmedFilter_CONFIRMED_FILTER_CONFIRMED,
+		Metadata:  map[string]string{"test": "test"},
+	}
+
+	l, err := as.List(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, len(l.Accounts), 1)
+	assert.Equal(t, l.Accounts[0].Id, a.Id)
+}
+
+func TestListInvalidOrder(t *testing.T) {
+	ctx := context.Background()
+	req := &account_service.ListAccountsRequest{
+		OrderBy: "hashed_password",
+	}
+
+	_, err := as.List(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.InvalidArgument)
+}
+
+func TestListNoSecrets(t *testing.T) {
+	truncate()
+
+	for i := 0; i < 3; i++ {
+		createAccountWithTokens(t)
+	}
+
+	ctx := context.Background()
+	for _, view := range []account_service.AccountView{
+		account_service.AccountView_FULL,
+		account_service.AccountView_BASIC,
+	} {
+		l, err := as.List(ctx, &account_service.ListAccountsRequest{View: view})
+		assert.Nil(t, err)
+		assert.Len(t, l.Accounts, 3)
+
+		for _, a := range l.Accounts {
+			assertNoSecrets(t, a)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/lileio/account_service/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const lockedMessage = "too many failed login attempts, try again later"
+
+// checkLocked fails with ResourceExhausted while any of the subjects are
+// locked out, a RetryInfo detail says how long for
+func (as AccountServer) checkLocked(subjects ...string) error {
+	for _, s := range subjects {
+		f, err := as.DB.ReadLoginFailures(s)
+		if err != nil {
+			return err
+		}
+
+		if locked, d := f.Locked(); locked {
+			return lockedError(d)
+		}
+	}
+
+	return nil
+}
+
+// loginFailed counts a failed login for each of the subjects. They're
+// recorded at once, so a wrong password for a registered email, counted
+// against its account as well, takes as long as one for an unknown email.
+func (as AccountServer) loginFailed(subjects ...string) error {
+	errs := make(chan error, len(subjects))
+	for _, s := range subjects {
+		go func(s string) {
+			_, err := as.DB.RecordLoginFailure(s)
+			errs <- err
+		}(s)
+	}
+
+	var err error
+	for range subjects {
+		if e := <-errs; e != nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// clearLoginFailures clears the failures counted for an account, after it
+// logs in or its password is reset
+func (as AccountServer) clearLoginFailures(a *database.Account) error {
+	return as.DB.ResetLoginFailures(
+		database.AccountSubject(a.ID),
+		database.EmailSubject(a.Email),
+	)
+}
+
+func lockedError(d time.Duration) error {
+	return retryError(lockedMessage, d)
+}
+
+// retryError fails with ResourceExhausted, a RetryInfo detail says how long
+// to wait before trying again
+func retryError(msg string, d time.Duration) error {
+	// round up so retrying after the delay always works
+	d = (d + time.Second - 1).Truncate(time.Second)
+
+	st, err := status.New(codes.ResourceExhausted, msg).
+		WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(d)})
+	if err != nil {
+		return grpc.Errorf(codes.ResourceExhausted, msg)
+	}
+
+	return st.Err()
+}
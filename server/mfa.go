@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/totp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryCodeCount is how many recovery codes GenerateRecoveryCodes returns
+var RecoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// enabledMfa returns the MFA enrolment of an account, nil if it doesn't have
+// MFA enabled
+func (as AccountServer) enabledMfa(accountID string) (*database.Mfa, error) {
+	m, err := as.DB.ReadMfa(accountID)
+	if err == database.ErrMfaNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.Enabled {
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+// mfaChallengeError issues a login challenge for m, returned as a
+// FailedPrecondition with the MfaChallenge as its detail so that clients not
+// expecting MFA see a failed login rather than an empty account
+func (as AccountServer) mfaChallengeError(m *database.Mfa) error {
+	c, err := as.DB.CreateMfaChallenge(m.AccountID)
+	if err != nil {
+		return err
+	}
+
+	st, err := status.New(codes.FailedPrecondition, "mfa code required").
+		WithDetails(&account_service.MfaChallenge{
+			Token:     c.Challenge,
+			ExpiresAt: timestampProto(c.ChallengeExpiresAt),
+		})
+	if err != nil {
+		return err
+	}
+
+	return st.Err()
+}
+
+// verifyMfaCode checks code is a TOTP code or recovery code of m that hasn't
+// been used yet, using it up
+func (as AccountServer) verifyMfaCode(m *database.Mfa, code string) error {
+	step, ok := totp.Validate(m.Secret, code, time.Now())
+
+	var err error
+	if ok {
+		err = as.DB.UseMfaStep(m.AccountID, step)
+	} else {
+		err = as.DB.UseRecoveryCode(m.AccountID, hashRecoveryCode(code))
+	}
+
+	if err == database.ErrMfaCodeUsed {
+		err = as.loginFailed(database.AccountSubject(m.AccountID))
+		if err != nil {
+			return err
+		}
+
+		return ErrMfaCodeIncorrect
+	}
+
+	return err
+}
+
+// generateRecoveryCodes returns n random codes formatted like abcd-efgh
+func generateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		b, err := database.GenerateRandomBytes(5)
+		if err != nil {
+			return nil, err
+		}
+
+		c := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = c[:4] + "-" + c[4:]
+	}
+
+	return codes, nil
+}
+
+// hashRecoveryCode returns the digest stored for a recovery code, ignoring
+// case and separators
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.Replace(code, "-", "", -1)
+	code = strings.Replace(code, " ", "", -1)
+	return database.HashToken(code)
+}
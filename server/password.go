@@ -0,0 +1,37 @@
+package server
+
+import (
+	"github.com/lileio/account_service/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validatePassword checks password against the password policy for the
+// account a, violations are returned as google.rpc.BadRequest details
+func validatePassword(password string, a *database.Account) error {
+	err := database.Passwords.Check(password, a)
+	if err == nil {
+		return nil
+	}
+
+	pe, ok := err.(*database.PasswordPolicyError)
+	if !ok {
+		return err
+	}
+
+	br := &errdetails.BadRequest{}
+	for _, v := range pe.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "password",
+			Description: v,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, pe.Error()).WithDetails(br)
+	if err != nil {
+		return err
+	}
+
+	return st.Err()
+}
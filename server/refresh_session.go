@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) RefreshSession(ctx context.Context, r *account_service.RefreshSessionRequest) (*account_service.Session, error) {
+	s, err := as.DB.RefreshSession(r.RefreshToken)
+	if err != nil {
+		if err == database.ErrSessionNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "session not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "refresh token expired")
+		}
+		return nil, err
+	}
+
+	return sessionDetailsFromSession(s), nil
+}
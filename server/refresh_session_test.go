@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRefreshSession(t *testing.T) {
+	ctx := context.Background()
+	s := createSession(t, createAccount(t))
+
+	req := &account_service.RefreshSessionRequest{RefreshToken: s.RefreshToken}
+	rs, err := as.RefreshSession(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Id, rs.Id)
+	assert.NotEmpty(t, rs.AccessToken)
+	assert.NotEqual(t, s.RefreshToken, rs.RefreshToken)
+
+	// the old refresh token can't be used again
+	_, err = as.RefreshSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestRefreshSessionExpired(t *testing.T) {
+	ctx := context.Background()
+
+	ttl := database.SessionRefreshTTL
+	database.SessionRefreshTTL = -time.Minute
+	defer func() { database.SessionRefreshTTL = ttl }()
+
+	s := createSession(t, createAccount(t))
+
+	req := &account_service.RefreshSessionRequest{RefreshToken: s.RefreshToken}
+	_, err := as.RefreshSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) RequestEmailChange(ctx context.Context, r *account_service.RequestEmailChangeRequest) (*account_service.EmailChange, error) {
+	if r.Id == "" {
+		return nil, ErrNoAccountID
+	}
+
+	if r.Email == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	a, err := as.DB.RequestEmailChange(r.Id, r.Email)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		if err == database.ErrEmailExists {
+			return nil, grpc.Errorf(codes.AlreadyExists, err.Error())
+		}
+		return nil, err
+	}
+
+	return &account_service.EmailChange{
+		AccountId: a.ID,
+		OldEmail:  a.Email,
+		NewEmail:  a.PendingEmail,
+		Token:     a.EmailChangeToken,
+		ExpiresAt: timestampProto(a.EmailChangeExpiresAt),
+	}, nil
+}
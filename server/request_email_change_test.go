@@ -0,0 +1,69 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func requestEmailChange(t *testing.T, a *account_service.Account) *account_service.EmailChange {
+	req := &account_service.RequestEmailChangeRequest{Id: a.Id, Email: "changed." + a.Email}
+	c, err := as.RequestEmailChange(context.Background(), req)
+	assert.Nil(t, err)
+	return c
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	c := requestEmailChange(t, a)
+	assert.Equal(t, a.Id, c.AccountId)
+	assert.Equal(t, a.Email, c.OldEmail)
+	assert.Equal(t, "changed."+a.Email, c.NewEmail)
+	assert.NotEmpty(t, c.Token)
+	assert.NotNil(t, c.ExpiresAt)
+
+	// the email isn't changed until it's confirmed
+	acc, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, a.Email, acc.Email)
+}
+
+func TestRequestEmailChangeTaken(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	other := createAccount(t)
+
+	req := &account_service.RequestEmailChangeRequest{Id: a.Id, Email: other.Email}
+	_, err := as.RequestEmailChange(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.AlreadyExists, grpc.Code(err))
+}
+
+func TestRequestEmailChangeInvalid(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	_, err := as.RequestEmailChange(ctx, &account_service.RequestEmailChangeRequest{Email: "x@localhost"})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+
+	_, err = as.RequestEmailChange(ctx, &account_service.RequestEmailChangeRequest{Id: a.Id})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
+
+func TestRequestEmailChangeNotFound(t *testing.T) {
+	ctx := context.Background()
+	req := &account_service.RequestEmailChangeRequest{
+		Id:    "00000000-0000-0000-0000-000000000000",
+		Email: "x@localhost",
+	}
+
+	_, err := as.RequestEmailChange(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
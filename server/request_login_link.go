@@ -0,0 +1,54 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) RequestLoginLink(ctx context.Context, r *account_service.RequestLoginLinkRequest) (*account_service.RequestLoginLinkResponse, error) {
+	if r.Email == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	a, err := as.DB.ReadByEmail(r.Email)
+	if err == database.ErrAccountNotFound && !EnumerationSafe {
+		return nil, grpc.Errorf(codes.NotFound, "account not found")
+	}
+
+	if err != nil && err != database.ErrAccountNotFound {
+		logrus.Errorf("login link error %v", err)
+		return nil, ErrInternal
+	}
+
+	// unknown emails still get a link that can't be used, so they're rate
+	// limited just like registered ones
+	accountID := ""
+	if a != nil {
+		accountID = a.ID
+	}
+
+	l, err := as.DB.CreateLoginLink(r.Email, accountID)
+	if limited, ok := err.(*database.RateLimitError); ok {
+		return nil, retryError("too many login links requested, try again later", limited.RetryAfter)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// there's nothing to send, succeeding lets callers show users the same
+	// message whether or not the email is registered
+	if a == nil {
+		return &account_service.RequestLoginLinkResponse{Email: r.Email}, nil
+	}
+
+	return &account_service.RequestLoginLinkResponse{
+		Email:     a.Email,
+		Token:     l.Token,
+		ExpiresAt: timestampProto(l.ExpiresAt),
+	}, nil
+}
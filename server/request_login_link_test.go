@@ -0,0 +1,76 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRequestLoginLink(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.RequestLoginLinkRequest{Email: a.Email}
+	res, err := as.RequestLoginLink(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Email, res.Email)
+	assert.NotEmpty(t, res.Token)
+	assert.NotNil(t, res.ExpiresAt)
+}
+
+func TestRequestLoginLinkNoEmail(t *testing.T) {
+	ctx := context.Background()
+	req := &account_service.RequestLoginLinkRequest{}
+	_, err := as.RequestLoginLink(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
+
+func TestRequestLoginLinkUnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	req := &account_service.RequestLoginLinkRequest{Email: "nobody@localhost"}
+	res, err := as.RequestLoginLink(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "nobody@localhost", res.Email)
+	assert.Empty(t, res.Token)
+	assert.Nil(t, res.ExpiresAt)
+}
+
+func TestRequestLoginLinkUnknownEmailUnsafe(t *testing.T) {
+	defer withEnumerationSafe(false)()
+
+	ctx := context.Background()
+	req := &account_service.RequestLoginLinkRequest{Email: "nobody@localhost"}
+	_, err := as.RequestLoginLink(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestRequestLoginLinkRateLimited(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	req := &account_service.RequestLoginLinkRequest{Email: a.Email}
+	for i := 0; i < database.LoginLinkLimit; i++ {
+		_, err := as.RequestLoginLink(ctx, req)
+		assert.Nil(t, err)
+	}
+
+	_, err := as.RequestLoginLink(ctx, req)
+	assertLocked(t, err)
+
+	// unknown emails are limited the same way
+	req = &account_service.RequestLoginLinkRequest{Email: "limited@localhost"}
+	for i := 0; i < database.LoginLinkLimit; i++ {
+		_, err = as.RequestLoginLink(ctx, req)
+		assert.Nil(t, err)
+	}
+
+	_, err = as.RequestLoginLink(ctx, req)
+	assertLocked(t, err)
+}
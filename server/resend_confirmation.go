@@ -0,0 +1,37 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) ResendConfirmation(ctx context.Context, r *account_service.ResendConfirmationRequest) (*account_service.ResendConfirmationResponse, error) {
+	if r.Email == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	a, err := as.DB.GenerateConfirmationToken(r.Email)
+	if err != nil {
+		// with EnumerationSafe there's nothing to send but the response
+		// looks the same
+		if EnumerationSafe && (err == database.ErrAccountNotFound || err == database.ErrConfirmed) {
+			return &account_service.ResendConfirmationResponse{}, nil
+		}
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		if err == database.ErrConfirmed {
+			return nil, grpc.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+
+	return &account_service.ResendConfirmationResponse{
+		Email:     a.Email,
+		Token:     a.ConfirmationToken,
+		ExpiresAt: timestampProto(a.ConfirmationExpiresAt),
+	}, nil
+}
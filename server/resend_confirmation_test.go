@@ -0,0 +1,61 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestResendConfirmation(t *testing.T) {
+	ctx := context.Background()
+	ac := createAccount(t)
+
+	req := &account_service.ResendConfirmationRequest{Email: ac.Email}
+	res, err := as.ResendConfirmation(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, ac.Email, res.Email)
+	assert.NotEmpty(t, res.Token)
+	assert.NotEqual(t, ac.ConfirmToken, res.Token)
+	assert.NotNil(t, res.ExpiresAt)
+
+	// only the new token confirms the account
+	_, err = as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: ac.ConfirmToken})
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+
+	_, err = as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: res.Token})
+	assert.Nil(t, err)
+}
+
+func TestResendConfirmationConfirmed(t *testing.T) {
+	ctx := context.Background()
+	ac := createAccount(t)
+
+	_, err := as.ConfirmAccount(ctx, &account_service.ConfirmAccountRequest{Token: ac.ConfirmToken})
+	assert.Nil(t, err)
+
+	req := &account_service.ResendConfirmationRequest{Email: ac.Email}
+	res, err := as.ResendConfirmation(ctx, req)
+	assert.Nil(t, err)
+	assert.Empty(t, res.Token)
+
+	defer withEnumerationSafe(false)()
+	_, err = as.ResendConfirmation(ctx, req)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
+
+func TestResendConfirmationUnknownEmail(t *testing.T) {
+	ctx := context.Background()
+
+	req := &account_service.ResendConfirmationRequest{Email: "nobody@localhost"}
+	res, err := as.ResendConfirmation(ctx, req)
+	assert.Nil(t, err)
+	assert.Empty(t, res.Token)
+
+	defer withEnumerationSafe(false)()
+	_, err = as.ResendConfirmation(ctx, req)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -11,18 +11,44 @@ import (
 )
 
 func (as AccountServer) ResetPassword(ctx context.Context, r *account_service.ResetPasswordRequest) (*account_service.Account, error) {
-	a := database.Account{}
+	ac, err := as.DB.ReadByPasswordToken(r.Token)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, err
+	}
 
-	err := a.HashPassword(r.Password)
+	err = validatePassword(r.Password, ac)
 	if err != nil {
 		return nil, err
 	}
 
-	ac, err := as.DB.UpdatePassword(r.Token, a.HashedPassword)
+	a := database.Account{}
+	err = a.HashPassword(r.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	ac, err = as.DB.UpdatePassword(r.Token, a.HashedPassword, a.PepperID)
 	if err != nil {
 		if err == database.ErrAccountNotFound {
 			return nil, grpc.Errorf(codes.NotFound, "account not found")
 		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "password reset token expired")
+		}
+		return nil, err
+	}
+
+	// a new password logs out everywhere
+	err = as.DB.RevokeAllSessions(ac.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.clearLoginFailures(ac)
+	if err != nil {
 		return nil, err
 	}
 
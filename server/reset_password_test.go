@@ -2,10 +2,14 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
 	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestResetPassword(t *testing.T) {
@@ -35,3 +39,136 @@ func TestResetPassword(t *testing.T) {
 	_, err = as.AuthenticateByEmail(ctx, ar)
 	assert.Nil(t, err)
 }
+
+func TestResetPasswordSingleUse(t *testing.T) {
+	ctx := context.Background()
+
+	ac := createAccount(t)
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{
+		Token:    res.Token,
+		Password: "somenewpassword",
+	}
+
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.Nil(t, err)
+
+	resetReq.Password = "anotherpassword"
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.NotFound)
+}
+
+func TestResetPasswordExpired(t *testing.T) {
+	ctx := context.Background()
+
+	ttl := database.PasswordResetTTL
+	database.PasswordResetTTL = -time.Minute
+	defer func() { database.PasswordResetTTL = ttl }()
+
+	ac := createAccount(t)
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{
+		Token:    res.Token,
+		Password: "somenewpassword",
+	}
+
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.FailedPrecondition)
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+
+	ac := createAccount(t)
+	s := createSession(t, ac)
+
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{
+		Token:    res.Token,
+		Password: "somenewpassword",
+	}
+
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.Nil(t, err)
+
+	refreshReq := &account_service.RefreshSessionRequest{RefreshToken: s.RefreshToken}
+	_, err = as.RefreshSession(ctx, refreshReq)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestResetPasswordUnlocks(t *testing.T) {
+	defer withLockoutThreshold(2)()
+	ctx := context.Background()
+	ac := createAccount(t)
+
+	wrong := &account_service.AuthenticateByEmailRequest{Email: ac.Email, Password: "incorrect password lol"}
+	for i := 0; i < 2; i++ {
+		_, err := as.AuthenticateByEmail(ctx, wrong)
+		assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+	}
+
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{Token: res.Token, Password: "somenewpassword"}
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.Nil(t, err)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: ac.Email, Password: "somenewpassword"}
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
+
+func TestResetPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	ac := createAccount(t)
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{Token: res.Token, Password: "short"}
+	_, err = as.ResetPassword(ctx, resetReq)
+	v := assertPasswordViolations(t, err)
+	assert.Equal(t, []string{"must be at least 8 characters"}, v)
+
+	// the token can still be used
+	resetReq.Password = "somenewpassword"
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.Nil(t, err)
+}
+
+func TestResetPasswordCancelsEmailChange(t *testing.T) {
+	ctx := context.Background()
+	ac := createAccount(t)
+	c := requestEmailChange(t, ac)
+
+	req := &account_service.GeneratePasswordTokenRequest{Email: ac.Email}
+	res, err := as.GeneratePasswordToken(ctx, req)
+	assert.Nil(t, err)
+
+	resetReq := &account_service.ResetPasswordRequest{
+		Token:    res.Token,
+		Password: "somenewpassword",
+	}
+
+	_, err = as.ResetPassword(ctx, resetReq)
+	assert.Nil(t, err)
+
+	_, err = as.ConfirmEmailChange(ctx, &account_service.ConfirmEmailChangeRequest{Token: c.Token})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/lileio/account_service"
+	context "golang.org/x/net/context"
+)
+
+func (as AccountServer) RevokeAllSessions(ctx context.Context, r *account_service.RevokeAllSessionsRequest) (*empty.Empty, error) {
+	if r.AccountId == "" {
+		return nil, ErrNoAccountID
+	}
+
+	err := as.DB.RevokeAllSessions(r.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
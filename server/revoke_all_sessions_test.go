@@ -0,0 +1,24 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+)
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	createSession(t, a)
+	createSession(t, a)
+
+	req := &account_service.RevokeAllSessionsRequest{AccountId: a.Id}
+	_, err := as.RevokeAllSessions(ctx, req)
+	assert.Nil(t, err)
+
+	res, err := as.ListSessions(ctx, &account_service.ListSessionsRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+	assert.Empty(t, res.Sessions)
+}
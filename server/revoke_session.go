@@ -0,0 +1,22 @@
+package server
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) RevokeSession(ctx context.Context, r *account_service.RevokeSessionRequest) (*empty.Empty, error) {
+	err := as.DB.RevokeSession(r.Id)
+	if err != nil {
+		if err == database.ErrSessionNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
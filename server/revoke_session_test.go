@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.Background()
+	s := createSession(t, createAccount(t))
+
+	_, err := as.RevokeSession(ctx, &account_service.RevokeSessionRequest{Id: s.Id})
+	assert.Nil(t, err)
+
+	req := &account_service.RefreshSessionRequest{RefreshToken: s.RefreshToken}
+	_, err = as.RefreshSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+
+	_, err = as.RevokeSession(ctx, &account_service.RevokeSessionRequest{Id: s.Id})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"os"
+	"strconv"
 	"time"
 
 	"google.golang.org/grpc"
@@ -9,9 +10,13 @@ import (
 
 	context "golang.org/x/net/context"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	account "github.com/lileio/account_service"
 	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/jwt"
+	"github.com/lileio/account_service/totp"
 	"github.com/lileio/image_service"
 	"github.com/lileio/lile"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -21,12 +26,41 @@ import (
 type AccountServer struct {
 	account.AccountServiceServer
 	DB database.Database
+	// Keys signs the tokens returned by IssueToken, it's nil unless
+	// JWT_KEYS_DIR is set
+	Keys *jwt.KeySet
 }
 
 var (
 	is image_service.ImageServiceClient
 
-	ErrNoAccount = grpc.Errorf(codes.InvalidArgument, "account is nil")
+	// EnumerationSafe hides whether an email is registered, unknown emails
+	// fail to log in the same way as a wrong password and get an empty
+	// response from GeneratePasswordToken. ENUMERATION_SAFE=false turns it
+	// off, restoring NotFound errors.
+	EnumerationSafe = true
+
+	// RequireConfirmation refuses password logins to accounts that haven't
+	// been confirmed once ConfirmationGracePeriod has passed since they were
+	// created. Set them with REQUIRE_CONFIRMATION and
+	// CONFIRMATION_GRACE_PERIOD, by default logins don't need a confirmed
+	// account.
+	RequireConfirmation     = false
+	ConfirmationGracePeriod time.Duration
+
+	ErrNoAccount       = grpc.Errorf(codes.InvalidArgument, "account is nil")
+	ErrNoAccountID     = grpc.Errorf(codes.InvalidArgument, "account id is required")
+	ErrVersionConflict = grpc.Errorf(codes.Aborted, "account has been modified, re-read and try again")
+	ErrEmailUpdate     = grpc.Errorf(codes.InvalidArgument, "email can't be changed by Update, use RequestEmailChange")
+	ErrNoSigningKeys   = grpc.Errorf(codes.FailedPrecondition, "token signing is not configured")
+	ErrLoginIncorrect  = grpc.Errorf(codes.PermissionDenied, "email or password incorrect")
+	ErrInternal        = grpc.Errorf(codes.Internal, "internal error")
+
+	ErrMfaRequired      = grpc.Errorf(codes.FailedPrecondition, "mfa code required")
+	ErrMfaCodeIncorrect = grpc.Errorf(codes.PermissionDenied, "mfa code incorrect")
+	ErrMfaNotEnrolled   = grpc.Errorf(codes.NotFound, "mfa not enrolled")
+	ErrMfaNotEnabled    = grpc.Errorf(codes.FailedPrecondition, "mfa is not enabled")
+	ErrMfaEnabled       = grpc.Errorf(codes.FailedPrecondition, "mfa already enabled, disable it first")
 )
 
 func NewAccountServer() *lile.Server {
@@ -34,7 +68,32 @@ func NewAccountServer() *lile.Server {
 	db.Migrate()
 	defer db.Close()
 
-	as := AccountServer{DB: db}
+	keys, err := jwt.KeySetFromEnv()
+	if err != nil {
+		logrus.Fatalf("jwt keys error %v", err)
+	}
+
+	if keys != nil {
+		go keys.Watch(time.Minute)
+	}
+
+	if safe, err := strconv.ParseBool(os.Getenv("ENUMERATION_SAFE")); err == nil {
+		EnumerationSafe = safe
+	}
+
+	if require, err := strconv.ParseBool(os.Getenv("REQUIRE_CONFIRMATION")); err == nil {
+		RequireConfirmation = require
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("CONFIRMATION_GRACE_PERIOD")); err == nil && d >= 0 {
+		ConfirmationGracePeriod = d
+	}
+
+	if iss := os.Getenv("MFA_ISSUER"); iss != "" {
+		totp.Issuer = iss
+	}
+
+	as := AccountServer{DB: db, Keys: keys}
 
 	impl := func(g *grpc.Server) {
 		account.RegisterAccountServiceServer(g, as)
@@ -50,10 +109,16 @@ func NewAccountServer() *lile.Server {
 			"GeneratePasswordToken": "account_service.password_token_generated",
 			"ResetPassword":         "account_service.password_reset",
 			"ConfirmAccount":        "account_service.account_confirmed",
+			"RequestLoginLink":      "account_service.login_link_requested",
+			"RequestEmailChange":    "account_service.email_change_requested",
+			"ConfirmEmailChange":    "account_service.email_changed",
+			"ResendConfirmation":    "account_service.confirmation_resent",
 		}),
 	)
 }
 
+// accountDetailsFromAccount returns the FULL view of an account, tokens are
+// never included so RPCs creating them have to add them explicitly.
 func accountDetailsFromAccount(a *database.Account) *account.Account {
 	imgs := map[string]*image_service.Image{}
 	for _, i := range a.Images {
@@ -61,16 +126,71 @@ func accountDetailsFromAccount(a *database.Account) *account.Account {
 	}
 
 	return &account.Account{
-		Id:                 a.ID,
-		Name:               a.Name,
-		Email:              a.Email,
-		Images:             imgs,
-		Metadata:           a.Metadata,
-		ConfirmToken:       a.ConfirmationToken,
-		PasswordResetToken: a.PasswordResetToken,
+		Id:          a.ID,
+		Name:        a.Name,
+		Email:       a.Email,
+		Images:      imgs,
+		Metadata:    a.Metadata,
+		Version:     a.Version,
+		Confirmed:   a.Confirmed(),
+		CreatedAt:   timestampProto(a.CreatedAt),
+		UpdatedAt:   timestampProto(a.UpdatedAt),
+		ConfirmedAt: timestampProto(a.ConfirmedAt),
+		LastLoginAt: timestampProto(a.LastLoginAt),
+	}
+}
+
+// accountView returns the fields of an account included in view
+func accountView(a *database.Account, view account.AccountView) *account.Account {
+	switch view {
+	case account.AccountView_BASIC:
+		return &account.Account{
+			Id:      a.ID,
+			Name:    a.Name,
+			Email:   a.Email,
+			Version: a.Version,
+		}
+	case account.AccountView_ADMIN:
+		acc := accountDetailsFromAccount(a)
+		acc.ConfirmTokenHash = a.ConfirmationTokenHash
+		acc.PasswordResetTokenHash = a.PasswordResetTokenHash
+		return acc
+	}
+
+	return accountDetailsFromAccount(a)
+}
+
+// sessionDetailsFromSession converts a session, its tokens are only set when
+// they have just been issued.
+func sessionDetailsFromSession(s *database.Session) *account.Session {
+	return &account.Session{
+		Id:                    s.ID,
+		AccountId:             s.AccountID,
+		AccessToken:           s.AccessToken,
+		RefreshToken:          s.RefreshToken,
+		AccessTokenExpiresAt:  timestampProto(s.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: timestampProto(s.RefreshTokenExpiresAt),
+		UserAgent:             s.UserAgent,
+		Device:                s.Device,
+		CreatedAt:             timestampProto(s.CreatedAt),
+		LastSeenAt:            timestampProto(s.LastSeenAt),
 	}
 }
 
+// timestampProto converts t, leaving zero or out of range times unset
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+
+	return ts
+}
+
 func (as AccountServer) storeImage(
 	ctx context.Context,
 	img *image_service.ImageStoreRequest,
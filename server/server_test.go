@@ -1,6 +1,7 @@
 package server
 
 import (
+	"io/ioutil"
 	"math/rand"
 	"strconv"
 	"testing"
@@ -8,11 +9,16 @@ import (
 
 	"golang.org/x/net/context"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	_ "github.com/lib/pq"
 	account "github.com/lileio/account_service"
 	"github.com/lileio/account_service/database"
+	"github.com/lileio/account_service/jwt"
+	"github.com/lileio/account_service/totp"
 	"github.com/lileio/image_service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -72,3 +78,172 @@ func createAccount(t *testing.T) *account.Account {
 	assert.Nil(t, err)
 	return account
 }
+
+// createAccountWithTokens creates an account with outstanding confirmation
+// and password reset tokens, both are set on the returned account
+func createAccountWithTokens(t *testing.T) *account.Account {
+	a := createAccount(t)
+	assert.NotEmpty(t, a.ConfirmToken)
+
+	req := &account.GeneratePasswordTokenRequest{Email: a.Email}
+	res, err := as.GeneratePasswordToken(context.Background(), req)
+	assert.Nil(t, err)
+
+	a.PasswordResetToken = res.Token
+	return a
+}
+
+func assertNoSecrets(t *testing.T, a *account.Account) {
+	assert.Empty(t, a.ConfirmToken)
+	assert.Empty(t, a.PasswordResetToken)
+}
+
+func createSession(t *testing.T, a *account.Account) *account.Session {
+	req := &account.CreateSessionRequest{
+		Email:     a.Email,
+		Password:  pass,
+		UserAgent: "curl/7.54",
+		Device:    "laptop",
+	}
+	s, err := as.CreateSession(context.Background(), req)
+	assert.Nil(t, err)
+	return s
+}
+
+// setupKeys gives the server an Ed25519 signing key
+func setupKeys(t *testing.T) {
+	if as.Keys != nil {
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "account_service_keys")
+	assert.Nil(t, err)
+
+	_, err = jwt.GenerateKey(dir, jwt.EdDSA)
+	assert.Nil(t, err)
+
+	as.Keys, err = jwt.LoadKeySet(dir)
+	assert.Nil(t, err)
+}
+
+// enableMfa enrols and activates MFA for an account, returning its secret.
+// It's activated with the code for the previous time step so mfaCode(t,
+// secret, 0) and mfaCode(t, secret, 1) can still be used.
+func enableMfa(t *testing.T, a *account.Account) string {
+	ctx := context.Background()
+	res, err := as.EnrolMfa(ctx, &account.EnrolMfaRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+
+	req := &account.ActivateMfaRequest{AccountId: a.Id, Code: mfaCode(t, res.Secret, -1)}
+	_, err = as.ActivateMfa(ctx, req)
+	assert.Nil(t, err)
+	return res.Secret
+}
+
+// mfaCode returns the TOTP code offset steps from now
+func mfaCode(t *testing.T, secret string, offset int64) string {
+	c, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	assert.Nil(t, err)
+	return c
+}
+
+// withLockoutThreshold lowers the lockout threshold for a test, call the
+// returned func to restore it
+func withLockoutThreshold(n int) func() {
+	threshold := database.LockoutThreshold
+	database.LockoutThreshold = n
+	return func() { database.LockoutThreshold = threshold }
+}
+
+// withEnumerationSafe sets EnumerationSafe, returning a func to restore it
+func withEnumerationSafe(safe bool) func() {
+	previous := EnumerationSafe
+	EnumerationSafe = safe
+	return func() { EnumerationSafe = previous }
+}
+
+// withRequireConfirmation sets RequireConfirmation and its grace period,
+// returning a func to restore them
+func withRequireConfirmation(require bool, grace time.Duration) func() {
+	previous, previousGrace := RequireConfirmation, ConfirmationGracePeriod
+	RequireConfirmation, ConfirmationGracePeriod = require, grace
+	return func() { RequireConfirmation, ConfirmationGracePeriod = previous, previousGrace }
+}
+
+// assertUnconfirmed checks err refuses a login to the unconfirmed account
+// with email
+func assertUnconfirmed(t *testing.T, err error, email string) {
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+
+	st, _ := status.FromError(err)
+	details := st.Details()
+	if !assert.Len(t, details, 1) {
+		return
+	}
+
+	pf, ok := details[0].(*errdetails.PreconditionFailure)
+	if assert.True(t, ok) && assert.Len(t, pf.Violations, 1) {
+		assert.Equal(t, ViolationUnconfirmed, pf.Violations[0].Type)
+		assert.Equal(t, email, pf.Violations[0].Subject)
+	}
+}
+
+// assertMfaChallenge checks err asks for a login to be completed with
+// VerifyMfa, returning the challenge
+func assertMfaChallenge(t *testing.T, err error) *account.MfaChallenge {
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+
+	st, _ := status.FromError(err)
+	details := st.Details()
+	if !assert.Len(t, details, 1) {
+		return &account.MfaChallenge{}
+	}
+
+	c, ok := details[0].(*account.MfaChallenge)
+	if !assert.True(t, ok) {
+		return &account.MfaChallenge{}
+	}
+
+	assert.NotEmpty(t, c.Token)
+	assert.NotNil(t, c.ExpiresAt)
+	return c
+}
+
+// assertPasswordViolations checks err rejects a password for breaking the
+// password policy, returning the violations
+func assertPasswordViolations(t *testing.T, err error) []string {
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+
+	st, _ := status.FromError(err)
+	details := st.Details()
+	if !assert.Len(t, details, 1) {
+		return nil
+	}
+
+	br, ok := details[0].(*errdetails.BadRequest)
+	assert.True(t, ok)
+
+	v := []string{}
+	for _, fv := range br.GetFieldViolations() {
+		assert.Equal(t, "password", fv.Field)
+		v = append(v, fv.Description)
+	}
+
+	return v
+}
+
+// assertLocked checks err is a lockout with a retry delay
+func assertLocked(t *testing.T, err error) {
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(err))
+
+	st, _ := status.FromError(err)
+	details := st.Details()
+	assert.Equal(t, 1, len(details))
+
+	if len(details) == 1 {
+		ri, ok := details[0].(*errdetails.RetryInfo)
+		assert.True(t, ok)
+		assert.True(t, ri.RetryDelay.Seconds > 0)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) UnlockAccount(ctx context.Context, r *account_service.UnlockAccountRequest) (*empty.Empty, error) {
+	a, err := as.DB.ReadByID(r.AccountId)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, err
+	}
+
+	err = as.clearLoginFailures(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
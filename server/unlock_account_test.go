@@ -0,0 +1,41 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestUnlockAccount(t *testing.T) {
+	defer withLockoutThreshold(2)()
+	ctx := context.Background()
+	a := createAccount(t)
+
+	wrong := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "incorrect password lol"}
+	for i := 0; i < 2; i++ {
+		_, err := as.AuthenticateByEmail(ctx, wrong)
+		assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+	}
+
+	right := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err := as.AuthenticateByEmail(ctx, right)
+	assertLocked(t, err)
+
+	_, err = as.UnlockAccount(ctx, &account_service.UnlockAccountRequest{AccountId: a.Id})
+	assert.Nil(t, err)
+
+	_, err = as.AuthenticateByEmail(ctx, right)
+	assert.Nil(t, err)
+}
+
+func TestUnlockAccountNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := as.UnlockAccount(ctx, &account_service.UnlockAccountRequest{AccountId: "missing"})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
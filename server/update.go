@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/lileio/account_service"
 	"github.com/lileio/account_service/database"
 	context "golang.org/x/net/context"
@@ -13,11 +15,34 @@ func (as AccountServer) Update(ctx context.Context, r *account_service.UpdateAcc
 		return nil, ErrNoAccount
 	}
 
+	fields, err := updateMaskFields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// without a mask the whole account is usually sent back, which is fine
+	// as long as its email is the one the account already has
+	if len(r.GetUpdateMask().GetPaths()) == 0 && r.Account.Email != "" {
+		err = as.checkEmailUnchanged(r.Id, r.Account.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	a := database.Account{
 		ID:       r.Id,
 		Name:     r.Account.Name,
-		Email:    r.Account.Email,
 		Metadata: r.Account.Metadata,
+		Version:  r.Version,
+	}
+
+	if r.Password != "" {
+		err = as.hashUpdatedPassword(&a, r.Password, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, "hashed_password")
 	}
 
 	if r.Image != nil {
@@ -29,6 +54,12 @@ func (as AccountServer) Update(ctx context.Context, r *account_service.UpdateAcc
 			return nil, err
 		}
 
+		// check the version before the old images are thrown away, the
+		// update itself checks it again
+		if r.Version != 0 && r.Version != ca.Version {
+			return nil, ErrVersionConflict
+		}
+
 		err = as.deleteImages(ctx, ca)
 		if err != nil {
 			return nil, err
@@ -38,15 +69,96 @@ func (as AccountServer) Update(ctx context.Context, r *account_service.UpdateAcc
 		if err != nil {
 			return nil, err
 		}
+
+		fields = append(fields, "images")
 	}
 
-	err := as.DB.Update(&a)
+	err = as.DB.Update(&a, fields...)
 	if err != nil {
 		if err == database.ErrAccountNotFound {
 			return nil, grpc.Errorf(codes.NotFound, "account not found")
 		}
+		if err == database.ErrVersionConflict {
+			return nil, ErrVersionConflict
+		}
 		return nil, err
 	}
 
 	return accountDetailsFromAccount(&a), nil
 }
+
+// checkEmailUnchanged fails with ErrEmailUpdate unless email is the account's
+// current email, emails are only changed once verified by RequestEmailChange
+// and ConfirmEmailChange
+func (as AccountServer) checkEmailUnchanged(ID, email string) error {
+	ca, err := as.DB.ReadByID(ID)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return err
+	}
+
+	if !strings.EqualFold(ca.Email, email) {
+		return ErrEmailUpdate
+	}
+
+	return nil
+}
+
+// hashUpdatedPassword sets the hashed password of a once password satisfies
+// the password policy, checked against the name the account will have after
+// the update
+func (as AccountServer) hashUpdatedPassword(a *database.Account, password string, fields []string) error {
+	ca, err := as.DB.ReadByID(a.ID)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return err
+	}
+
+	for _, f := range fields {
+		if f == "name" {
+			ca.Name = a.Name
+		}
+	}
+
+	err = validatePassword(password, ca)
+	if err != nil {
+		return err
+	}
+
+	return a.HashPassword(password)
+}
+
+// updateMaskPaths are the account fields an update_mask may contain, the
+// email is changed by RequestEmailChange instead
+var updateMaskPaths = map[string]bool{
+	"name":     true,
+	"metadata": true,
+}
+
+// updateMaskFields returns the database fields to update for r, every
+// updateMaskPath when no update mask is given.
+func updateMaskFields(r *account_service.UpdateAccountRequest) ([]string, error) {
+	paths := r.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return []string{"name", "metadata"}, nil
+	}
+
+	fields := make([]string, len(paths))
+	for i, p := range paths {
+		if p == "email" {
+			return nil, ErrEmailUpdate
+		}
+
+		if !updateMaskPaths[p] {
+			return nil, grpc.Errorf(codes.InvalidArgument, "unknown update mask path %q", p)
+		}
+
+		fields[i] = p
+	}
+
+	return fields, nil
+}
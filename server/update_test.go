@@ -3,6 +3,10 @@ package server
 import (
 	"testing"
 
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
 	"github.com/lileio/account_service"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -15,8 +19,7 @@ func TestUpdateSuccess(t *testing.T) {
 	ctx := context.Background()
 	a := createAccount(t)
 
-	email := "somethingnew@gmail.com"
-	a.Email = email
+	a.Name = "Alex C"
 
 	ar := &account_service.UpdateAccountRequest{
 		Id:      a.Id,
@@ -26,11 +29,36 @@ func TestUpdateSuccess(t *testing.T) {
 	a2, err := as.Update(ctx, ar)
 	assert.Nil(t, err)
 	assert.NotEmpty(t, a2.Id)
-	assert.Equal(t, a2.Email, email)
+	assert.Equal(t, a2.Name, "Alex C")
 
 	a3, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a2.Id})
 	assert.Nil(t, err)
-	assert.Equal(t, a3.Email, email)
+	assert.Equal(t, a3.Name, "Alex C")
+}
+
+func TestUpdateEmail(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+	email := a.Email
+
+	a.Email = "somethingnew@gmail.com"
+	ar := &account_service.UpdateAccountRequest{
+		Id:      a.Id,
+		Account: a,
+	}
+
+	_, err := as.Update(ctx, ar)
+	assert.Equal(t, ErrEmailUpdate, err)
+
+	ar.UpdateMask = &field_mask.FieldMask{Paths: []string{"name", "email"}}
+	_, err = as.Update(ctx, ar)
+	assert.Equal(t, ErrEmailUpdate, err)
+
+	a2, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, email, a2.Email)
 }
 
 func TestUpdateNotExist(t *testing.T) {
@@ -46,3 +74,131 @@ func TestUpdateNotExist(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, a2)
 }
+
+func TestUpdateMask(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.UpdateAccountRequest{
+		Id:         a.Id,
+		Account:    &account_service.Account{Name: "Alex C"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name"}},
+	}
+
+	a2, err := as.Update(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a2.Name, "Alex C")
+	assert.Equal(t, a2.Email, a.Email)
+	assert.Equal(t, a2.Metadata, a.Metadata)
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.UpdateAccountRequest{
+		Id:         a.Id,
+		Account:    &account_service.Account{Metadata: map[string]string{"plan": "pro"}},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"metadata"}},
+	}
+
+	_, err := as.Update(ctx, ar)
+	assert.Nil(t, err)
+
+	a2, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, a2.Metadata, map[string]string{"plan": "pro"})
+	assert.Equal(t, a2.Name, a.Name)
+}
+
+func TestUpdateUnknownMask(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.UpdateAccountRequest{
+		Id:         a.Id,
+		Account:    a,
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"confirm_token"}},
+	}
+
+	_, err := as.Update(ctx, ar)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.InvalidArgument)
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	truncate()
+
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ar := &account_service.UpdateAccountRequest{
+		Id:      a.Id,
+		Account: &account_service.Account{Name: "Alex C", Email: a.Email},
+		Version: a.Version,
+	}
+
+	a2, err := as.Update(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a2.Version, a.Version+1)
+
+	ar.Account.Name = "Alex D"
+	_, err = as.Update(ctx, ar)
+	assert.NotNil(t, err)
+	assert.Equal(t, grpc.Code(err), codes.Aborted)
+
+	ar.Version = a2.Version
+	a3, err := as.Update(ctx, ar)
+	assert.Nil(t, err)
+	assert.Equal(t, a3.Name, "Alex D")
+}
+
+func TestUpdatePassword(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	ur := &account_service.UpdateAccountRequest{
+		Id:         a.Id,
+		Account:    &account_service.Account{},
+		Password:   "somenewpassword",
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"metadata"}},
+	}
+
+	_, err := as.Update(ctx, ur)
+	assert.Nil(t, err)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: "somenewpassword"}
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
+
+func TestUpdatePasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+
+	// checked against the new name rather than the old one
+	a.Name = "Jonathan"
+	ur := &account_service.UpdateAccountRequest{
+		Id:       a.Id,
+		Account:  a,
+		Password: "jonathan1",
+	}
+
+	_, err := as.Update(ctx, ur)
+	v := assertPasswordViolations(t, err)
+	assert.Equal(t, []string{"must not contain your email or name"}, v)
+
+	ra, err := as.GetById(ctx, &account_service.GetByIdRequest{Id: a.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, name, ra.Name)
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err = as.AuthenticateByEmail(ctx, ar)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) ValidateSession(ctx context.Context, r *account_service.ValidateSessionRequest) (*account_service.Session, error) {
+	s, err := as.DB.TouchSession(r.AccessToken)
+	if err != nil {
+		if err == database.ErrSessionNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "session not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "access token expired")
+		}
+		return nil, err
+	}
+
+	return sessionDetailsFromSession(s), nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValidateSession(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	s := createSession(t, a)
+
+	time.Sleep(10 * time.Millisecond)
+	req := &account_service.ValidateSessionRequest{AccessToken: s.AccessToken}
+	vs, err := as.ValidateSession(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Id, vs.Id)
+	assert.Equal(t, a.Id, vs.AccountId)
+	assert.Empty(t, vs.AccessToken)
+	assert.Empty(t, vs.RefreshToken)
+
+	created, _ := ptypes.Timestamp(s.LastSeenAt)
+	seen, _ := ptypes.Timestamp(vs.LastSeenAt)
+	assert.True(t, seen.After(created))
+
+	_, err = as.ValidateSession(ctx, &account_service.ValidateSessionRequest{AccessToken: "unknown"})
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestValidateSessionExpired(t *testing.T) {
+	ctx := context.Background()
+
+	ttl := database.SessionAccessTTL
+	database.SessionAccessTTL = -time.Minute
+	defer func() { database.SessionAccessTTL = ttl }()
+
+	s := createSession(t, createAccount(t))
+
+	req := &account_service.ValidateSessionRequest{AccessToken: s.AccessToken}
+	_, err := as.ValidateSession(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+}
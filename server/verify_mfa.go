@@ -0,0 +1,54 @@
+package server
+
+import (
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (as AccountServer) VerifyMfa(ctx context.Context, r *account_service.VerifyMfaRequest) (*account_service.Account, error) {
+	// challenges are used up by any attempt, a wrong code means logging in
+	// with the password again
+	m, err := as.DB.ConsumeMfaChallenge(r.Challenge)
+	if err != nil {
+		if err == database.ErrNoMfaChallenge {
+			return nil, grpc.Errorf(codes.NotFound, "mfa challenge not found")
+		}
+		if err == database.ErrTokenExpired {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "mfa challenge expired")
+		}
+		return nil, err
+	}
+
+	err = as.checkLocked(database.AccountSubject(m.AccountID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.verifyMfaCode(m, r.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := as.DB.ReadByID(m.AccountID)
+	if err != nil {
+		if err == database.ErrAccountNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, err
+	}
+
+	err = as.checkCanLogIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.loggedIn(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountDetailsFromAccount(a), nil
+}
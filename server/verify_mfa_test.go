@@ -0,0 +1,146 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lileio/account_service"
+	"github.com/lileio/account_service/database"
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func mfaChallenge(t *testing.T, a *account_service.Account) string {
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err := as.AuthenticateByEmail(context.Background(), ar)
+	return assertMfaChallenge(t, err).Token
+}
+
+func TestVerifyMfa(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+
+	req := &account_service.VerifyMfaRequest{
+		Challenge: mfaChallenge(t, a),
+		Code:      mfaCode(t, secret, 0),
+	}
+
+	acc, err := as.VerifyMfa(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+	assert.Equal(t, a.Email, acc.Email)
+	assertNoSecrets(t, acc)
+
+	// the challenge can't be used again
+	_, err = as.VerifyMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestVerifyMfaCodeReused(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+	code := mfaCode(t, secret, 0)
+
+	req := &account_service.VerifyMfaRequest{Challenge: mfaChallenge(t, a), Code: code}
+	_, err := as.VerifyMfa(ctx, req)
+	assert.Nil(t, err)
+
+	req = &account_service.VerifyMfaRequest{Challenge: mfaChallenge(t, a), Code: code}
+	_, err = as.VerifyMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestVerifyMfaWrongCode(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+
+	challenge := mfaChallenge(t, a)
+	req := &account_service.VerifyMfaRequest{Challenge: challenge, Code: mfaCode(t, secret, 5)}
+	_, err := as.VerifyMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+
+	// a wrong code uses up the challenge
+	req = &account_service.VerifyMfaRequest{Challenge: challenge, Code: mfaCode(t, secret, 0)}
+	_, err = as.VerifyMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+}
+
+func TestVerifyMfaRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	enableMfa(t, a)
+
+	gr := &account_service.GenerateRecoveryCodesRequest{AccountId: a.Id}
+	res, err := as.GenerateRecoveryCodes(ctx, gr)
+	assert.Nil(t, err)
+
+	// recovery codes ignore case and separators
+	code := strings.ToUpper(strings.Replace(res.Codes[0], "-", "", -1))
+	req := &account_service.VerifyMfaRequest{Challenge: mfaChallenge(t, a), Code: code}
+	acc, err := as.VerifyMfa(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, a.Id, acc.Id)
+
+	req = &account_service.VerifyMfaRequest{Challenge: mfaChallenge(t, a), Code: res.Codes[0]}
+	_, err = as.VerifyMfa(ctx, req)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestVerifyMfaWrongCodesLock(t *testing.T) {
+	defer withLockoutThreshold(2)()
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+
+	for i := 0; i < 2; i++ {
+		req := &account_service.VerifyMfaRequest{Challenge: mfaChallenge(t, a), Code: mfaCode(t, secret, 5)}
+		_, err := as.VerifyMfa(ctx, req)
+		assert.Equal(t, codes.PermissionDenied, grpc.Code(err))
+	}
+
+	ar := &account_service.AuthenticateByEmailRequest{Email: a.Email, Password: pass}
+	_, err := as.AuthenticateByEmail(ctx, ar)
+	assertLocked(t, err)
+}
+
+func TestVerifyMfaEmailLocked(t *testing.T) {
+	defer withLockoutThreshold(2)()
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+	challenge := mfaChallenge(t, a)
+
+	// the email was locked out after the challenge was issued
+	for i := 0; i < 2; i++ {
+		_, err := as.DB.RecordLoginFailure(database.EmailSubject(a.Email))
+		assert.Nil(t, err)
+	}
+
+	req := &account_service.VerifyMfaRequest{Challenge: challenge, Code: mfaCode(t, secret, 0)}
+	_, err := as.VerifyMfa(ctx, req)
+	assertLocked(t, err)
+}
+
+func TestVerifyMfaUnconfirmed(t *testing.T) {
+	ctx := context.Background()
+	a := createAccount(t)
+	secret := enableMfa(t, a)
+	challenge := mfaChallenge(t, a)
+
+	// confirmation became required after the challenge was issued
+	defer withRequireConfirmation(true, 0)()
+
+	req := &account_service.VerifyMfaRequest{Challenge: challenge, Code: mfaCode(t, secret, 0)}
+	_, err := as.VerifyMfa(ctx, req)
+	assertUnconfirmed(t, err, a.Email)
+}
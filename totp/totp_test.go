@@ -0,0 +1,59 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the SHA1 test vectors from RFC 6238, truncated to 6 digits
+func TestCodeRFC6238(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, want := range vectors {
+		c, err := Code(secret, Step(time.Unix(unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, want, c)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	c, err := Code(secret, Step(now))
+	assert.Nil(t, err)
+
+	step, ok := Validate(secret, c, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// codes from the previous period are still accepted
+	_, ok = Validate(secret, c, now.Add(Period))
+	assert.True(t, ok)
+
+	_, ok = Validate(secret, c, now.Add(3*Period))
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("alex@localhost", "JBSWY3DPEHPK3PXP")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/account_service:alex@localhost?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=account_service")
+}